@@ -1,5 +1,5 @@
 provider "contentstack" {
   api_key          = var.contentstack_api_key
-  host             = "https://api.contentstack.io/"
+  region           = "na"
   management_token = var.contentstack_management_token
 }
@@ -0,0 +1,20 @@
+resource "contentstack_content_type" "blog_post" {
+  uid         = "blog_post"
+  title       = "Blog Post"
+  description = "exported from the web app"
+  schema_json = jsonencode([
+    {
+      uid          = "title"
+      display_name = "Title"
+      data_type    = "text"
+      mandatory    = true
+      unique       = true
+    },
+    {
+      uid          = "seo"
+      display_name = "SEO"
+      data_type    = "global_field"
+      reference_to = contentstack_global_field.seo.uid
+    }
+  ])
+}
@@ -4,9 +4,30 @@ resource "contentstack_global_field" "common_metadata" {
   description = "created by terraform"
   fields = [
     {
-      uid          = "title"
-      display_name = "Title"
+      uid          = "headline"
+      display_name = "Headline"
       data_type    = "text"
+    },
+    {
+      uid            = "priority"
+      display_name   = "Priority"
+      data_type      = "number"
+      default_number = 1
     }
   ]
 }
+
+resource "contentstack_global_field" "seo" {
+  uid   = "seo"
+  title = "SEO"
+  schema_json = jsonencode([
+    {
+      uid          = "meta_title"
+      display_name = "Meta Title"
+      data_type    = "text"
+      field_metadata = {
+        description = "title shown in search results"
+      }
+    }
+  ])
+}
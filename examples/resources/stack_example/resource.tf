@@ -0,0 +1,17 @@
+resource "contentstack_stack" "feature" {
+  name             = "feature-team"
+  description      = "ephemeral stack for the feature team"
+  master_locale    = "en-us"
+  organization_uid = var.contentstack_organization_uid
+}
+
+resource "contentstack_stack_share" "developer" {
+  stack_api_key = contentstack_stack.feature.api_key
+  email         = "developer@example.com"
+  roles         = [var.contentstack_developer_role_uid]
+}
+
+resource "contentstack_environment" "staging" {
+  stack_api_key = contentstack_stack.feature.api_key
+  name          = "staging"
+}
@@ -0,0 +1,267 @@
+package contentstacktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	// CassetteModeEnvVar selects whether tests which use cassettes record
+	// the real API or replay what was recorded.
+	CassetteModeEnvVar = "CONTENTSTACK_CASSETTE_MODE"
+
+	// CassetteModeRecord sends requests to the real API and saves the
+	// sanitized interactions; it needs real credentials.
+	CassetteModeRecord = "record"
+	// CassetteModeReplay answers requests from a saved cassette without any
+	// network access; it is the default.
+	CassetteModeReplay = "replay"
+
+	// Redacted replaces every credential in a cassette.
+	Redacted = "REDACTED"
+)
+
+// CassetteMode returns the mode selected by CassetteModeEnvVar.
+func CassetteMode() string {
+	if os.Getenv(CassetteModeEnvVar) == CassetteModeRecord {
+		return CassetteModeRecord
+	}
+	return CassetteModeReplay
+}
+
+// Cassette is a recording of the requests a test sent and the responses it got.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a sanitized request; the URL is its path and query.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a sanitized response.
+type RecordedResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+}
+
+// LoadCassette reads a cassette saved by a Recorder.
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("parsing cassette %s: %w", path, err)
+	}
+
+	return &c, nil
+}
+
+// Save writes the cassette as indented JSON, creating its directory.
+func (c *Cassette) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(b, '\n'), 0o600)
+}
+
+// secretProperties matches the credentials which requests and responses
+// carry in their bodies, as JSON properties or form values.
+var secretProperties = regexp.MustCompile(
+	`("(?:api_key|authtoken|password|tfa_token|access_token|refresh_token|client_secret)"\s*:\s*)"[^"]*"` +
+		`|\b((?:client_secret|refresh_token|access_token)=)[^&\s]*`,
+)
+
+// sanitizer scrubs credentials out of recorded text.
+type sanitizer struct {
+	secrets []string
+}
+
+func (s sanitizer) sanitize(text string) string {
+	for _, secret := range s.secrets {
+		if secret != "" {
+			text = strings.ReplaceAll(text, secret, Redacted)
+		}
+	}
+
+	return secretProperties.ReplaceAllStringFunc(text, func(match string) string {
+		groups := secretProperties.FindStringSubmatch(match)
+		if groups[1] != "" {
+			return groups[1] + `"` + Redacted + `"`
+		}
+		return groups[2] + Redacted
+	})
+}
+
+// Recorder records requests and their responses. Credentials are never
+// recorded: request headers are left out, and the given secrets and well
+// known credential properties are replaced by Redacted.
+type Recorder struct {
+	sanitizer sanitizer
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder starts an empty recording; secrets are the credential values
+// to scrub, such as the api key and management token.
+func NewRecorder(secrets ...string) *Recorder {
+	return &Recorder{sanitizer: sanitizer{secrets: secrets}}
+}
+
+// Wrap returns an http.RoundTripper which sends requests on through next
+// and records them.
+func (r *Recorder) Wrap(next http.RoundTripper) http.RoundTripper {
+	return recordingTransport{recorder: r, next: next}
+}
+
+type recordingTransport struct {
+	recorder *Recorder
+	next     http.RoundTripper
+}
+
+func (t recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil && body != nil {
+			requestBody, _ = io.ReadAll(body)
+			_ = body.Close()
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	t.recorder.record(Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.RequestURI(),
+			Body:   string(requestBody),
+		},
+		Response: RecordedResponse{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        string(responseBody),
+		},
+	})
+
+	return resp, nil
+}
+
+func (r *Recorder) record(i Interaction) {
+	i.Request.URL = r.sanitizer.sanitize(i.Request.URL)
+	i.Request.Body = r.sanitizer.sanitize(i.Request.Body)
+	i.Response.Body = r.sanitizer.sanitize(i.Response.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+}
+
+// Cassette returns what has been recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := Cassette{Interactions: make([]Interaction, len(r.cassette.Interactions))}
+	copy(c.Interactions, r.cassette.Interactions)
+	return &c
+}
+
+// Replayer is an http.RoundTripper which answers requests from a cassette
+// without any network access. Each request is answered by the first
+// interaction not yet replayed with the same method and URL path and query;
+// the host and credentials of the request are ignored.
+type Replayer struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewReplayer replays the interactions of a cassette.
+func NewReplayer(c *Cassette) *Replayer {
+	return &Replayer{cassette: c, used: make([]bool, len(c.Interactions))}
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uri := req.URL.RequestURI()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || interaction.Request.Method != req.Method || interaction.Request.URL != uri {
+			continue
+		}
+		r.used[i] = true
+
+		header := make(http.Header)
+		if interaction.Response.ContentType != "" {
+			header.Set("Content-Type", interaction.Response.ContentType)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction left for %s %s; record the cassette again with %s=%s", req.Method, uri, CassetteModeEnvVar, CassetteModeRecord)
+}
+
+// Unused returns the interactions which have not been replayed, which means
+// the test no longer sends the requests that were recorded.
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i, used := range r.used {
+		if !used {
+			unused = append(unused, r.cassette.Interactions[i])
+		}
+	}
+	return unused
+}
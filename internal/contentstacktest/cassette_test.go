@@ -0,0 +1,137 @@
+package contentstacktest
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	t.Parallel()
+
+	s := sanitizer{secrets: []string{"blt123", ""}}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"secret anywhere", `/v3/stacks?api_key=blt123&x=1`, `/v3/stacks?api_key=REDACTED&x=1`},
+		{"json property", `{"user":{"email":"a@b.c","password":"hunter2"}}`, `{"user":{"email":"a@b.c","password":"REDACTED"}}`},
+		{"json property with spaces", `{"authtoken" : "abc"}`, `{"authtoken" : "REDACTED"}`},
+		{"form value", `grant_type=refresh_token&refresh_token=abc&client_secret=def`, `grant_type=refresh_token&refresh_token=REDACTED&client_secret=REDACTED`},
+		{"nothing to scrub", `{"environment":{"name":"staging"}}`, `{"environment":{"name":"staging"}}`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := s.sanitize(tt.text); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	api := NewServer()
+	recorder := NewRecorder(api.APIKey, api.ManagementToken)
+	client := &http.Client{Transport: recorder.Wrap(http.DefaultTransport)}
+
+	send := func(client *http.Client, host, method, path, body string) (int, string) {
+		t.Helper()
+
+		req, err := http.NewRequest(method, host+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("got unexpected error: %s", err)
+		}
+		req.Header.Set("api_key", api.APIKey)
+		req.Header.Set("authorization", api.ManagementToken)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("got unexpected error: %s", err)
+		}
+		defer resp.Body.Close()
+
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("got unexpected error: %s", err)
+		}
+		return resp.StatusCode, string(b)
+	}
+
+	createStatus, createBody := send(client, api.URL, http.MethodPost, "/v3/environments", `{"environment":{"name":"staging"}}`)
+	getStatus, getBody := send(client, api.URL, http.MethodGet, "/v3/environments/staging?api_key="+api.APIKey, "")
+	api.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "test.json")
+	if err := recorder.Cassette().Save(path); err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	if strings.Contains(string(b), api.APIKey) || strings.Contains(string(b), api.ManagementToken) {
+		t.Errorf("expected the credentials to be scrubbed, got %s", b)
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	if len(cassette.Interactions) != 2 {
+		t.Fatalf("expected 2 interactions, got %d", len(cassette.Interactions))
+	}
+	if got := cassette.Interactions[1].Request.URL; got != "/v3/environments/staging?api_key=REDACTED" {
+		t.Errorf("expected the api key to be scrubbed from the URL, got %q", got)
+	}
+
+	// the replay answers from the cassette although the API is gone, and
+	// ignores the host and credentials of the requests
+	replayer := NewReplayer(cassette)
+	client = &http.Client{Transport: replayer}
+
+	if status, body := send(client, "https://api.contentstack.io", http.MethodPost, "/v3/environments", `{"environment":{"name":"staging"}}`); status != createStatus || body != createBody {
+		t.Errorf("expected %d %s, got %d %s", createStatus, createBody, status, body)
+	}
+	if unused := replayer.Unused(); len(unused) != 1 {
+		t.Errorf("expected 1 unused interaction, got %v", unused)
+	}
+	if status, body := send(client, "https://api.contentstack.io", http.MethodGet, "/v3/environments/staging?api_key=REDACTED", ""); status != getStatus || body != getBody {
+		t.Errorf("expected %d %s, got %d %s", getStatus, getBody, status, body)
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("expected no unused interactions, got %v", unused)
+	}
+
+	// every interaction is replayed once
+	req, err := http.NewRequest(http.MethodPost, "https://api.contentstack.io/v3/environments", nil)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	if _, err := replayer.RoundTrip(req); err == nil || !strings.Contains(err.Error(), CassetteModeEnvVar) {
+		t.Errorf("expected an error telling to record the cassette again, got %v", err)
+	}
+}
+
+func TestCassetteMode(t *testing.T) {
+	t.Setenv(CassetteModeEnvVar, "")
+	if got := CassetteMode(); got != CassetteModeReplay {
+		t.Errorf("expected %q by default, got %q", CassetteModeReplay, got)
+	}
+
+	t.Setenv(CassetteModeEnvVar, CassetteModeRecord)
+	if got := CassetteMode(); got != CassetteModeRecord {
+		t.Errorf("expected %q, got %q", CassetteModeRecord, got)
+	}
+}
@@ -0,0 +1,576 @@
+// Package contentstacktest provides an in-process fake of the Contentstack
+// Content Management API for tests which must run without credentials or
+// network access.
+//
+// The fake keeps environments, global fields, content types, their entries
+// and locales of one stack in memory, along with the stacks of one
+// organization. Like the real API it assigns uids to environments, stamps
+// created/updated times and users, increments `_version` on every change and
+// answers errors with an `error_message`, `error_code` and per-field
+// `errors` body. Properties it does not know about are stored and returned
+// unchanged.
+package contentstacktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Record is one stack, environment, global field, content type or locale as
+// the API returns it.
+type Record map[string]interface{}
+
+// Collection describes one kind of record served by the fake.
+type Collection struct {
+	// Path is the URL path of the collection, e.g. "/v3/environments".
+	Path string
+	// Singular and Plural are the keys wrapping records in request and response bodies.
+	Singular string
+	Plural   string
+	// Title is the name of the kind in notices and error messages.
+	Title string
+	// Key is the property records are addressed by in the URL.
+	Key string
+	// NotFoundCode is the error_code of the response to a missing record.
+	NotFoundCode int
+}
+
+// The collections served by the fake.
+var (
+	Environments = Collection{Path: "/v3/environments", Singular: "environment", Plural: "environments", Title: "Environment", Key: "name", NotFoundCode: 141}
+	GlobalFields = Collection{Path: "/v3/global_fields", Singular: "global_field", Plural: "global_fields", Title: "Global Field", Key: "uid", NotFoundCode: 118}
+	ContentTypes = Collection{Path: "/v3/content_types", Singular: "content_type", Plural: "content_types", Title: "Content Type", Key: "uid", NotFoundCode: 118}
+	Locales      = Collection{Path: "/v3/locales", Singular: "locale", Plural: "locales", Title: "Language", Key: "code", NotFoundCode: 247}
+
+	// Stacks are addressed by the api_key header rather than the URL, and an
+	// unknown api key is answered with a 412.
+	Stacks = Collection{Path: "/v3/stacks", Singular: "stack", Plural: "stacks", Title: "Stack", Key: "api_key", NotFoundCode: 412}
+)
+
+var collections = []Collection{Environments, GlobalFields, ContentTypes, Locales}
+
+// Server is a fake Contentstack API for a single stack.
+//
+// Stacks created through the API only support the /v3/stacks endpoints; the
+// other collections belong to the stack of APIKey.
+type Server struct {
+	*httptest.Server
+
+	// APIKey and ManagementToken are the credentials the fake accepts; the
+	// management token is scoped to the stack of APIKey.
+	APIKey          string
+	ManagementToken string
+	// Authtoken is the token of the user, which is accepted for every stack
+	// and is needed to create stacks in the organization OrganizationUID.
+	Authtoken       string
+	OrganizationUID string
+	// UserUID is reported as the creator and last editor of records.
+	UserUID string
+
+	mu       sync.Mutex
+	now      func() time.Time
+	nextUID  int
+	records  map[string]map[string]Record
+	requests map[string]int
+	// entries holds the entries of each content type by its uid.
+	entries map[string][]Record
+}
+
+// NewServer starts a fake API; callers should Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		APIKey:          "blt0000000000000000",
+		ManagementToken: "cs0000000000000000000000",
+		Authtoken:       "blt00000000000000t1",
+		OrganizationUID: "blt00000000000000o1",
+		UserUID:         "blt00000000000000a1",
+		now:             time.Now,
+		records:         make(map[string]map[string]Record),
+		requests:        make(map[string]int),
+		entries:         make(map[string][]Record),
+	}
+	for _, c := range append(collections, Stacks) {
+		s.records[c.Path] = make(map[string]Record)
+	}
+	s.Add(Stacks, Record{"api_key": s.APIKey, "name": "Fake Stack", "description": "", "master_locale": "en-us", "org_uid": s.OrganizationUID})
+	// like a new stack, the fake starts with its master locale
+	s.Add(Locales, Record{"code": "en-us", "name": "English - United States", "fallback_locale": nil})
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Get returns a copy of the record with the given key.
+func (s *Server) Get(c Collection, id string) (Record, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, r := s.find(c, id)
+	if r == nil {
+		return nil, false
+	}
+	return r.copy(), true
+}
+
+// Modify changes a record out of band, as someone editing it in the web app
+// would, and reports whether the record exists.
+func (s *Server) Modify(c Collection, id string, f func(Record)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, r := s.find(c, id)
+	if r == nil {
+		return false
+	}
+	f(r)
+	s.touch(r)
+	return true
+}
+
+// Add creates a record out of band, as someone using the web app would, and
+// returns its uid. The record is not validated.
+func (s *Server) Add(c Collection, r Record) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r = r.copy()
+	uid, _ := r["uid"].(string)
+	if c.Key != "uid" {
+		uid = s.newUID()
+		r["uid"] = uid
+	}
+	s.insert(c, uid, r)
+	return uid
+}
+
+// AddEntry creates an entry of a content type out of band, as an editor
+// would, and returns its uid. The fake only lists entries, by the uid of
+// their content type, and deletes them along with it.
+func (s *Server) AddEntry(contentTypeUID string, r Record) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r = r.copy()
+	uid := s.newUID()
+	r["uid"] = uid
+	s.entries[contentTypeUID] = append(s.entries[contentTypeUID], r)
+	return uid
+}
+
+// Requests returns how many requests the fake has received for a method and
+// URL path, such as "GET" and "/v3/locales".
+func (s *Server) Requests(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[method+" "+path]
+}
+
+// Remove deletes a record out of band and reports whether it existed.
+func (s *Server) Remove(c Collection, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uid, r := s.find(c, id)
+	if r == nil {
+		return false
+	}
+	delete(s.records[c.Path], uid)
+	return true
+}
+
+// apiError is the error body of the Contentstack API.
+type apiError struct {
+	ErrorMessage string              `json:"error_message"`
+	ErrorCode    int                 `json:"error_code"`
+	Errors       map[string][]string `json:"errors,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code int, message string, errors map[string][]string) {
+	writeJSON(w, status, apiError{ErrorMessage: message, ErrorCode: code, Errors: errors})
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// a stack is created in an organization rather than in a stack
+	creatingStack := r.URL.Path == Stacks.Path && r.Method == http.MethodPost
+	apiKey := r.Header.Get("api_key")
+	if _, stack := s.find(Stacks, apiKey); !creatingStack && (stack == nil || r.URL.Path != Stacks.Path && apiKey != s.APIKey) {
+		writeError(w, http.StatusPreconditionFailed, 412, "Api key is not valid.", map[string][]string{"api_key": {"is not valid."}})
+		return
+	}
+	byUser := s.Authtoken != "" && r.Header.Get("authtoken") == s.Authtoken
+	if !byUser && (creatingStack || apiKey != s.APIKey || r.Header.Get("authorization") != s.ManagementToken) {
+		writeError(w, http.StatusUnauthorized, 105, "You're not allowed in here unless you're logged in.", map[string][]string{"authorization": {"is not valid."}})
+		return
+	}
+
+	s.requests[r.Method+" "+r.URL.Path]++
+
+	if r.URL.Path == Stacks.Path {
+		s.serveStack(w, r, apiKey)
+		return
+	}
+
+	// the entries of a content type are listed below it
+	if id := strings.TrimPrefix(r.URL.Path, ContentTypes.Path+"/"); id != r.URL.Path && strings.HasSuffix(id, "/entries") && r.Method == http.MethodGet {
+		s.listEntries(w, r, strings.TrimSuffix(id, "/entries"))
+		return
+	}
+
+	for _, c := range collections {
+		if r.URL.Path == c.Path {
+			switch r.Method {
+			case http.MethodGet:
+				s.list(w, c)
+				return
+			case http.MethodPost:
+				s.create(w, r, c)
+				return
+			}
+		}
+
+		if id := strings.TrimPrefix(r.URL.Path, c.Path+"/"); id != r.URL.Path && id != "" && !strings.Contains(id, "/") {
+			uid, record := s.find(c, id)
+			if record == nil {
+				writeError(w, http.StatusUnprocessableEntity, c.NotFoundCode, fmt.Sprintf("%s was not found.", c.Title), map[string][]string{c.Key: {"is not valid."}})
+				return
+			}
+			switch r.Method {
+			case http.MethodGet:
+				writeJSON(w, http.StatusOK, map[string]interface{}{c.Singular: record})
+				return
+			case http.MethodPut:
+				s.update(w, r, c, uid, record)
+				return
+			case http.MethodDelete:
+				// like the real API, a global field in use by content types
+				// is only deleted when forced
+				if users := s.contentTypesUsing(uid); c.Path == GlobalFields.Path && len(users) > 0 && r.URL.Query().Get("force") != "true" {
+					writeError(w, http.StatusUnprocessableEntity, 115, fmt.Sprintf("%s is referred in content types %s.", c.Title, strings.Join(users, ", ")), nil)
+					return
+				}
+				delete(s.records[c.Path], uid)
+				if c.Path == ContentTypes.Path {
+					delete(s.entries, uid)
+				}
+				writeJSON(w, http.StatusOK, map[string]string{"notice": fmt.Sprintf("%s deleted successfully.", c.Title)})
+				return
+			}
+		}
+	}
+
+	writeError(w, http.StatusNotFound, 404, "The requested URL was not found on this server.", nil)
+}
+
+// serveStack creates a stack, or reads, updates or deletes the stack of the
+// api_key header.
+func (s *Server) serveStack(w http.ResponseWriter, r *http.Request, apiKey string) {
+	switch r.Method {
+	case http.MethodPost:
+		record, ok := decode(w, r, Stacks)
+		if !ok {
+			return
+		}
+		errors := make(map[string][]string)
+		if r.Header.Get("organization_uid") != s.OrganizationUID {
+			errors["organization_uid"] = []string{"is not valid."}
+		}
+		if name, _ := record["name"].(string); name == "" {
+			errors["name"] = []string{"can't be blank."}
+		}
+		if len(errors) > 0 {
+			writeError(w, http.StatusUnprocessableEntity, 119, "Stack creation failed. Please try again.", errors)
+			return
+		}
+		if _, ok := record["master_locale"]; !ok {
+			record["master_locale"] = "en-us"
+		}
+		uid := s.newUID()
+		record["uid"] = uid
+		record["api_key"] = s.newUID()
+		record["org_uid"] = s.OrganizationUID
+		s.insert(Stacks, uid, record)
+
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"notice": "Stack created successfully.",
+			"stack":  record,
+		})
+		return
+	}
+
+	uid, record := s.find(Stacks, apiKey)
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"stack": record})
+	case http.MethodPut:
+		changes, ok := decode(w, r, Stacks)
+		if !ok {
+			return
+		}
+		// only the name and description of a stack can be changed
+		for _, k := range []string{"name", "description"} {
+			if v, ok := changes[k]; ok {
+				record[k] = v
+			}
+		}
+		s.touch(record)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"notice": "Stack updated successfully.",
+			"stack":  record,
+		})
+	case http.MethodDelete:
+		delete(s.records[Stacks.Path], uid)
+		writeJSON(w, http.StatusOK, map[string]string{"notice": "Stack deleted successfully."})
+	default:
+		writeError(w, http.StatusNotFound, 404, "The requested URL was not found on this server.", nil)
+	}
+}
+
+// find returns the record addressed by its key, which is how the API
+// addresses it, and its uid; an environment cannot be addressed by its uid.
+func (s *Server) find(c Collection, id string) (string, Record) {
+	for uid, r := range s.records[c.Path] {
+		if r[c.Key] == id {
+			return uid, r
+		}
+	}
+	return "", nil
+}
+
+func (s *Server) list(w http.ResponseWriter, c Collection) {
+	records := make([]Record, 0, len(s.records[c.Path]))
+	for _, r := range s.records[c.Path] {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return fmt.Sprint(records[i][c.Key]) < fmt.Sprint(records[j][c.Key])
+	})
+	writeJSON(w, http.StatusOK, map[string]interface{}{c.Plural: records})
+}
+
+// listEntries lists a page of the entries of a content type, given by the
+// limit and skip query parameters, with their total count when
+// include_count=true.
+func (s *Server) listEntries(w http.ResponseWriter, r *http.Request, contentTypeUID string) {
+	if _, ct := s.find(ContentTypes, contentTypeUID); ct == nil {
+		writeError(w, http.StatusUnprocessableEntity, ContentTypes.NotFoundCode, fmt.Sprintf("%s was not found.", ContentTypes.Title), map[string][]string{"uid": {"is not valid."}})
+		return
+	}
+
+	entries := s.entries[contentTypeUID]
+	skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+	page := make([]Record, 0, limit)
+	for i := skip; i >= 0 && i < len(entries) && len(page) < limit; i++ {
+		page = append(page, entries[i])
+	}
+
+	body := map[string]interface{}{"entries": page}
+	if r.URL.Query().Get("include_count") == "true" {
+		body["count"] = len(entries)
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// decode reads the record wrapped in a request body.
+func decode(w http.ResponseWriter, r *http.Request, c Collection) (Record, bool) {
+	var body map[string]Record
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body[c.Singular] == nil {
+		writeError(w, http.StatusBadRequest, 400, "Bad request.", map[string][]string{c.Singular: {"is not valid JSON."}})
+		return nil, false
+	}
+	return body[c.Singular], true
+}
+
+// validate checks the required and unique properties of a record and the
+// display names of the fields of its schema.
+func (s *Server) validate(c Collection, uid string, r Record) map[string][]string {
+	errors := make(map[string][]string)
+
+	required := []string{c.Key}
+	if c.Key == "uid" {
+		required = append(required, "title")
+	}
+	for _, p := range required {
+		if v, _ := r[p].(string); v == "" {
+			errors[p] = append(errors[p], "can't be blank.")
+		}
+	}
+
+	for otherUID, other := range s.records[c.Path] {
+		if otherUID != uid && other[c.Key] == r[c.Key] {
+			errors[c.Key] = append(errors[c.Key], "is not unique.")
+		}
+	}
+
+	// like the real API, report problems with the fields of a schema by
+	// their position, such as "schema.3.display_name"
+	schema, _ := r["schema"].([]interface{})
+	displayNames := make(map[string]bool, len(schema))
+	for i, f := range schema {
+		field, _ := f.(map[string]interface{})
+		name, _ := field["display_name"].(string)
+		if displayNames[name] {
+			key := fmt.Sprintf("schema.%d.display_name", i)
+			errors[key] = append(errors[key], "is not unique.")
+		}
+		displayNames[name] = true
+	}
+
+	if len(errors) == 0 {
+		return nil
+	}
+	return errors
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, c Collection) {
+	record, ok := decode(w, r, c)
+	if !ok {
+		return
+	}
+
+	uid, _ := record["uid"].(string)
+	if c.Key != "uid" {
+		uid = s.newUID()
+		record["uid"] = uid
+	}
+
+	if errors := s.validate(c, uid, record); errors != nil {
+		writeError(w, http.StatusUnprocessableEntity, 119, fmt.Sprintf("%s creation failed. Please try again.", c.Title), errors)
+		return
+	}
+
+	s.insert(c, uid, record)
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"notice":   fmt.Sprintf("%s created successfully.", c.Title),
+		c.Singular: record,
+	})
+}
+
+// newUID assigns a uid to a record which is not addressed by its uid.
+func (s *Server) newUID() string {
+	s.nextUID++
+	return fmt.Sprintf("blt%016x", s.nextUID)
+}
+
+// insert stores a new record.
+func (s *Server) insert(c Collection, uid string, record Record) {
+	now := s.timestamp()
+	record["created_at"] = now
+	record["created_by"] = s.UserUID
+	record["updated_at"] = now
+	record["updated_by"] = s.UserUID
+	record["_version"] = 1
+	s.records[c.Path][uid] = record
+}
+
+// contentTypesUsing returns the sorted uids of the content types whose
+// schema includes the global field with the given uid.
+func (s *Server) contentTypesUsing(globalFieldUID string) []string {
+	var uids []string
+	for uid, r := range s.records[ContentTypes.Path] {
+		if schemaUses(r["schema"], globalFieldUID) {
+			uids = append(uids, uid)
+		}
+	}
+	sort.Strings(uids)
+	return uids
+}
+
+// schemaUses reports whether a schema, or the schema of one of its groups,
+// has a global_field field referring to globalFieldUID.
+func schemaUses(schema interface{}, globalFieldUID string) bool {
+	fields, _ := schema.([]interface{})
+	for _, f := range fields {
+		field, _ := f.(map[string]interface{})
+		if field["data_type"] == "global_field" && field["reference_to"] == globalFieldUID {
+			return true
+		}
+		if schemaUses(field["schema"], globalFieldUID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, c Collection, uid string, existing Record) {
+	changes, ok := decode(w, r, c)
+	if !ok {
+		return
+	}
+
+	// the uid and server managed properties cannot be changed
+	record := existing.copy()
+	for k, v := range changes {
+		switch k {
+		case "uid", "created_at", "created_by", "updated_at", "updated_by", "_version":
+			continue
+		}
+		record[k] = v
+	}
+
+	if errors := s.validate(c, uid, record); errors != nil {
+		writeError(w, http.StatusUnprocessableEntity, 121, fmt.Sprintf("%s update failed. Please try again.", c.Title), errors)
+		return
+	}
+
+	s.touch(record)
+	s.records[c.Path][uid] = record
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"notice":   fmt.Sprintf("%s updated successfully.", c.Title),
+		c.Singular: record,
+	})
+}
+
+// touch records a change to a record.
+func (s *Server) touch(r Record) {
+	r["updated_at"] = s.timestamp()
+	r["updated_by"] = s.UserUID
+	// copies decode numbers as float64
+	switch v := r["_version"].(type) {
+	case int:
+		r["_version"] = v + 1
+	case float64:
+		r["_version"] = int(v) + 1
+	default:
+		r["_version"] = 1
+	}
+}
+
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// copy returns a deep copy of the record, so that callers cannot change the
+// fake's state behind its back.
+func (r Record) copy() Record {
+	b, err := json.Marshal(r)
+	if err != nil {
+		panic(err)
+	}
+	var c Record
+	if err := json.Unmarshal(b, &c); err != nil {
+		panic(err)
+	}
+	return c
+}
@@ -0,0 +1,286 @@
+package contentstacktest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func do(t *testing.T, s *Server, method, path, body string) (int, map[string]interface{}) {
+	t.Helper()
+
+	return doWithHeaders(t, s, method, path, body, map[string]string{"api_key": s.APIKey, "authorization": s.ManagementToken})
+}
+
+func doWithHeaders(t *testing.T, s *Server, method, path, body string, headers map[string]string) (int, map[string]interface{}) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	return resp.StatusCode, result
+}
+
+func TestServerEnvironmentLifecycle(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer()
+	defer s.Close()
+
+	status, body := do(t, s, http.MethodPost, "/v3/environments", `{"environment":{"name":"staging","urls":[{"locale":"en-us","url":"https://staging.example.com"}]}}`)
+	if status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %v", status, body)
+	}
+	created, _ := body["environment"].(map[string]interface{})
+	uid, _ := created["uid"].(string)
+	if uid == "" || created["_version"] != float64(1) || created["created_by"] != s.UserUID {
+		t.Fatalf("expected a uid, version 1 and creator, got %v", created)
+	}
+
+	status, body = do(t, s, http.MethodPost, "/v3/environments", `{"environment":{"name":"staging"}}`)
+	if status != http.StatusUnprocessableEntity || body["errors"] == nil {
+		t.Errorf("expected a duplicate name to be rejected with errors, got %d: %v", status, body)
+	}
+
+	// environments are addressed by name and renamed in place
+	status, body = do(t, s, http.MethodPut, "/v3/environments/staging", `{"environment":{"name":"preview","uid":"ignored"}}`)
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %v", status, body)
+	}
+	updated, _ := body["environment"].(map[string]interface{})
+	if updated["uid"] != uid || updated["name"] != "preview" || updated["_version"] != float64(2) {
+		t.Errorf("expected the environment to be renamed at version 2, got %v", updated)
+	}
+
+	// but not by uid
+	status, body = do(t, s, http.MethodGet, "/v3/environments/"+uid, "")
+	if status != http.StatusUnprocessableEntity || body["error_code"] != float64(Environments.NotFoundCode) {
+		t.Errorf("expected a not found error, got %d: %v", status, body)
+	}
+
+	if !s.Modify(Environments, "preview", func(r Record) { r["deploy_content"] = true }) {
+		t.Fatal("expected to modify the environment")
+	}
+	if r, _ := s.Get(Environments, "preview"); r["_version"] != float64(3) {
+		t.Errorf("expected an out of band change to bump the version, got %v", r["_version"])
+	}
+
+	if status, body = do(t, s, http.MethodDelete, "/v3/environments/preview", ""); status != http.StatusOK {
+		t.Errorf("expected status 200, got %d: %v", status, body)
+	}
+	status, body = do(t, s, http.MethodGet, "/v3/environments/preview", "")
+	if status != http.StatusUnprocessableEntity || body["error_code"] != float64(Environments.NotFoundCode) {
+		t.Errorf("expected a not found error, got %d: %v", status, body)
+	}
+}
+
+func TestServerStackLifecycle(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer()
+	defer s.Close()
+
+	if status, body := do(t, s, http.MethodPost, "/v3/stacks", `{"stack":{"name":"Blog"}}`); status != http.StatusUnauthorized {
+		t.Errorf("expected a management token not to create stacks, got %d: %v", status, body)
+	}
+
+	status, body := doWithHeaders(t, s, http.MethodPost, "/v3/stacks", `{"stack":{"name":"Blog"}}`, map[string]string{"authtoken": s.Authtoken, "organization_uid": s.OrganizationUID})
+	if status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %v", status, body)
+	}
+	created, _ := body["stack"].(map[string]interface{})
+	apiKey, _ := created["api_key"].(string)
+	if apiKey == "" || created["master_locale"] != "en-us" || created["org_uid"] != s.OrganizationUID {
+		t.Fatalf("expected an api key, the default master locale and the organization, got %v", created)
+	}
+
+	// the stack is addressed by its api key
+	asUser := map[string]string{"api_key": apiKey, "authtoken": s.Authtoken}
+	status, body = doWithHeaders(t, s, http.MethodPut, "/v3/stacks", `{"stack":{"name":"Blog","description":"posts","master_locale":"fr-fr"}}`, asUser)
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %v", status, body)
+	}
+	updated, _ := body["stack"].(map[string]interface{})
+	if updated["description"] != "posts" || updated["master_locale"] != "en-us" {
+		t.Errorf("expected only the description to change, got %v", updated)
+	}
+
+	// the management token belongs to another stack
+	if status, body = doWithHeaders(t, s, http.MethodGet, "/v3/stacks", "", map[string]string{"api_key": apiKey, "authorization": s.ManagementToken}); status != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %d: %v", status, body)
+	}
+
+	if status, body = doWithHeaders(t, s, http.MethodDelete, "/v3/stacks", "", asUser); status != http.StatusOK {
+		t.Errorf("expected status 200, got %d: %v", status, body)
+	}
+	status, body = doWithHeaders(t, s, http.MethodGet, "/v3/stacks", "", asUser)
+	if status != http.StatusPreconditionFailed || body["error_code"] != float64(Stacks.NotFoundCode) {
+		t.Errorf("expected the api key of a deleted stack to be rejected, got %d: %v", status, body)
+	}
+}
+
+func TestServerGlobalFieldValidation(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer()
+	defer s.Close()
+
+	status, body := do(t, s, http.MethodPost, "/v3/global_fields", `{"global_field":{"uid":"seo","schema":[]}}`)
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d: %v", status, body)
+	}
+	errors, _ := body["errors"].(map[string]interface{})
+	if _, ok := errors["title"]; !ok {
+		t.Errorf("expected an error on the title, got %v", body)
+	}
+
+	status, body = do(t, s, http.MethodPost, "/v3/global_fields", `{"global_field":{"uid":"seo","title":"SEO","schema":[{"uid":"keywords","data_type":"text","x_unknown":1}]}}`)
+	if status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %v", status, body)
+	}
+
+	status, body = do(t, s, http.MethodPut, "/v3/global_fields/seo", `{"global_field":{"schema":[{"uid":"a","display_name":"A"},{"uid":"b","display_name":"A"}]}}`)
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d: %v", status, body)
+	}
+	errors, _ = body["errors"].(map[string]interface{})
+	if _, ok := errors["schema.1.display_name"]; !ok {
+		t.Errorf("expected an error on the display name of the second field, got %v", body)
+	}
+
+	r, ok := s.Get(GlobalFields, "seo")
+	if !ok {
+		t.Fatal("expected the global field to exist")
+	}
+	schema, _ := r["schema"].([]interface{})
+	if len(schema) != 1 {
+		t.Fatalf("expected one field, got %v", r["schema"])
+	}
+	if field, _ := schema[0].(map[string]interface{}); field["x_unknown"] != float64(1) {
+		t.Errorf("expected unknown properties to be kept, got %v", r["schema"])
+	}
+}
+
+func TestServerAuthentication(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer()
+	defer s.Close()
+
+	req, err := http.NewRequest(http.MethodGet, s.URL+"/v3/environments", nil)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	req.Header.Set("api_key", s.APIKey)
+	req.Header.Set("authorization", "wrong")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected status 401, got %d", resp.StatusCode)
+	}
+}
+
+func TestServerGlobalFieldInUse(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer()
+	defer s.Close()
+
+	if status, body := do(t, s, http.MethodPost, "/v3/global_fields", `{"global_field":{"uid":"seo","title":"SEO","schema":[]}}`); status != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %v", status, body)
+	}
+	s.Add(ContentTypes, Record{"uid": "blog_post", "title": "Blog Post", "schema": []interface{}{
+		map[string]interface{}{"uid": "meta", "data_type": "group", "schema": []interface{}{
+			map[string]interface{}{"uid": "seo", "data_type": "global_field", "reference_to": "seo"},
+		}},
+	}})
+
+	status, body := do(t, s, http.MethodDelete, "/v3/global_fields/seo", "")
+	if message, _ := body["error_message"].(string); status != http.StatusUnprocessableEntity || !strings.Contains(message, "blog_post") {
+		t.Errorf("expected a global field in use not to be deleted, got %d: %v", status, body)
+	}
+
+	if status, body = do(t, s, http.MethodDelete, "/v3/global_fields/seo?force=true", ""); status != http.StatusOK {
+		t.Errorf("expected a forced delete to succeed, got %d: %v", status, body)
+	}
+}
+
+func TestServerEntries(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer()
+	defer s.Close()
+
+	s.Add(ContentTypes, Record{"uid": "blog_post", "title": "Blog Post", "schema": []interface{}{}})
+	for _, title := range []string{"Hello", "World", "Again"} {
+		s.AddEntry("blog_post", Record{"title": title})
+	}
+
+	status, body := do(t, s, http.MethodGet, "/v3/content_types/blog_post/entries?limit=2&include_count=true", "")
+	if entries, _ := body["entries"].([]interface{}); status != http.StatusOK || len(entries) != 2 || body["count"] != float64(3) {
+		t.Errorf("expected a page of 2 of 3 entries, got %d: %v", status, body)
+	}
+
+	status, body = do(t, s, http.MethodGet, "/v3/content_types/missing/entries", "")
+	if status != http.StatusUnprocessableEntity || body["error_code"] != float64(ContentTypes.NotFoundCode) {
+		t.Errorf("expected a not found error, got %d: %v", status, body)
+	}
+
+	// deleting the content type deletes its entries
+	if status, body = do(t, s, http.MethodDelete, "/v3/content_types/blog_post", ""); status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %v", status, body)
+	}
+	s.Add(ContentTypes, Record{"uid": "blog_post", "title": "Blog Post", "schema": []interface{}{}})
+	status, body = do(t, s, http.MethodGet, "/v3/content_types/blog_post/entries?include_count=true", "")
+	if status != http.StatusOK || body["count"] != float64(0) {
+		t.Errorf("expected no entries, got %d: %v", status, body)
+	}
+}
+
+func TestServerLocales(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer()
+	defer s.Close()
+
+	s.Add(Locales, Record{"code": "fr-fr", "name": "French - France", "fallback_locale": "en-us"})
+
+	status, body := do(t, s, http.MethodGet, "/v3/locales", "")
+	if status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %v", status, body)
+	}
+	locales, _ := body["locales"].([]interface{})
+	if len(locales) != 2 {
+		t.Fatalf("expected the master locale and the added one, got %v", body)
+	}
+	master, _ := locales[0].(map[string]interface{})
+	if master["code"] != "en-us" {
+		t.Errorf("expected en-us first, got %v", master)
+	}
+
+	if n := s.Requests(http.MethodGet, "/v3/locales"); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+}
@@ -0,0 +1,743 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/davidalpert/go-contentstack/v1/management"
+	cschema "github.com/davidalpert/go-contentstack/v1/schema"
+	"github.com/go-resty/resty/v2"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// apiClient calls the Contentstack Management API for one stack.
+//
+// The provider owns the resty client rather than going through
+// go-contentstack's management.Client, which keeps its client private, so
+// that it can set the transport, timeouts, retries, rate limit and
+// authentication, and reach endpoints and payloads which the library does not
+// model yet.
+type apiClient struct {
+	resty *resty.Client
+}
+
+// newAPIClient creates a client with the settings of management.NewClient.
+func newAPIClient(cfg *management.Configuration) *apiClient {
+	headers := map[string]string{
+		"Accept":        "application/json",
+		"Content-Type":  "application/json",
+		"Authorization": cfg.Token,
+	}
+	if cfg.Key != "" {
+		headers["api_key"] = cfg.Key
+	}
+	if cfg.UserAgent != "" {
+		headers["User-Agent"] = cfg.UserAgent
+	}
+
+	return &apiClient{
+		resty: resty.New().
+			SetTimeout(1 * time.Minute).
+			SetBaseURL(cfg.Host).
+			SetHeaders(headers),
+	}
+}
+
+// newRequest starts a request bound to the context of the Terraform operation,
+// so that cancelling the operation stops the request and any retries.
+func newRequest(ctx context.Context, c *apiClient) *resty.Request {
+	return c.resty.R().SetContext(ctx)
+}
+
+// checkResponse turns a failed request or an unexpected status into an error;
+// an error response of the API becomes an *apiError.
+func checkResponse(endpoint string, expectedStatus int, resp *resty.Response, err error) error {
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode() != expectedStatus {
+		return newAPIError(endpoint, resp.StatusCode(), resp.Status(), resp.Body())
+	}
+
+	return nil
+}
+
+// apiError is an error response of the Contentstack API.
+type apiError struct {
+	Endpoint   string
+	StatusCode int
+	Status     string
+	Body       string
+
+	// Code and Message are the error_code and error_message of the response.
+	Code    int
+	Message string
+	// Errors holds the messages about each offending property of the
+	// request, keyed by its dotted path such as "schema.3.uid".
+	Errors map[string][]string
+}
+
+type apiErrorBody struct {
+	ErrorCode    int                        `json:"error_code"`
+	ErrorMessage string                     `json:"error_message"`
+	Errors       map[string]json.RawMessage `json:"errors"`
+}
+
+func newAPIError(endpoint string, statusCode int, status string, body []byte) *apiError {
+	e := &apiError{Endpoint: endpoint, StatusCode: statusCode, Status: status, Body: string(body)}
+
+	var b apiErrorBody
+	if json.Unmarshal(body, &b) != nil {
+		return e
+	}
+
+	e.Code = b.ErrorCode
+	e.Message = b.ErrorMessage
+	for key, raw := range b.Errors {
+		if e.Errors == nil {
+			e.Errors = make(map[string][]string, len(b.Errors))
+		}
+		e.Errors[key] = errorMessages(raw)
+	}
+
+	return e
+}
+
+// errorMessages decodes the messages about one property, which the API gives
+// as a list of strings, a single string or, rarely, some other JSON value.
+func errorMessages(raw json.RawMessage) []string {
+	var messages []string
+	if json.Unmarshal(raw, &messages) == nil {
+		return messages
+	}
+	var message string
+	if json.Unmarshal(raw, &message) == nil {
+		return []string{message}
+	}
+	return []string{string(raw)}
+}
+
+// ErrorKeys returns the keys of Errors in order.
+func (e *apiError) ErrorKeys() []string {
+	keys := make([]string, 0, len(e.Errors))
+	for key := range e.Errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// PropertyError describes the problem with one property, e.g. "name is not unique.".
+func (e *apiError) PropertyError(key string) string {
+	return key + " " + strings.Join(e.Errors[key], " ")
+}
+
+func (e *apiError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("calling %#v  %s: %s", e.Endpoint, e.Status, e.Body)
+	}
+
+	msg := fmt.Sprintf("calling %#v  %s: %s", e.Endpoint, e.Status, e.Message)
+	for _, key := range e.ErrorKeys() {
+		msg += " " + e.PropertyError(key)
+	}
+	if e.Code != 0 {
+		msg += fmt.Sprintf(" (error code %d)", e.Code)
+	}
+	return msg
+}
+
+// notFoundErrorCodes are the error codes with which the API answers, with a
+// 422 status, requests for objects which do not exist.
+var notFoundErrorCodes = map[int]bool{
+	118: true, // content type or global field
+	141: true, // environment
+}
+
+// isNotFound reports whether err is the API saying that the object asked
+// for does not exist, e.g. because it was deleted outside Terraform.
+func isNotFound(err error) bool {
+	var e *apiError
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.StatusCode == http.StatusNotFound || notFoundErrorCodes[e.Code]
+}
+
+// isStackNotFound reports whether err is the API rejecting the api key of a
+// stack, which is how it answers requests to a stack which was deleted.
+func isStackNotFound(err error) bool {
+	var e *apiError
+	if !errors.As(err, &e) {
+		return false
+	}
+	_, ok := e.Errors["api_key"]
+	return ok || isNotFound(err)
+}
+
+// globalFieldJSON is a GlobalField whose schema is kept as raw JSON so that
+// field properties not modeled by go-contentstack survive a round trip.
+type globalFieldJSON struct {
+	Title       string          `json:"title"`
+	UID         string          `json:"uid"`
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	// Version is only read; the API ignores it on update.
+	Version int `json:"_version,omitempty"`
+}
+
+type globalFieldJSONWrapper struct {
+	GlobalField *globalFieldJSON `json:"global_field"`
+}
+
+func getOneGlobalFieldJSON(ctx context.Context, c *apiClient, uid string) (*globalFieldJSON, error) {
+	endpoint := fmt.Sprintf("/v3/global_fields/%s", uid)
+
+	var r globalFieldJSONWrapper
+	resp, err := newRequest(ctx, c).SetResult(&r).Get(endpoint)
+	if err := checkResponse(endpoint, http.StatusOK, resp, err); err != nil {
+		return nil, err
+	}
+
+	return r.GlobalField, nil
+}
+
+func createGlobalFieldJSON(ctx context.Context, c *apiClient, g *globalFieldJSON) (*globalFieldJSON, error) {
+	endpoint := "/v3/global_fields?include_branch=false"
+
+	var r globalFieldJSONWrapper
+	resp, err := newRequest(ctx, c).SetBody(globalFieldJSONWrapper{GlobalField: g}).SetResult(&r).Post(endpoint)
+	if err := checkResponse(endpoint, http.StatusCreated, resp, err); err != nil {
+		return nil, err
+	}
+
+	return r.GlobalField, nil
+}
+
+func updateGlobalFieldJSON(ctx context.Context, c *apiClient, g *globalFieldJSON) (*globalFieldJSON, error) {
+	endpoint := fmt.Sprintf("/v3/global_fields/%s", g.UID)
+
+	var r globalFieldJSONWrapper
+	resp, err := newRequest(ctx, c).SetBody(globalFieldJSONWrapper{GlobalField: g}).SetResult(&r).Put(endpoint)
+	if err := checkResponse(endpoint, http.StatusOK, resp, err); err != nil {
+		return nil, err
+	}
+
+	return r.GlobalField, nil
+}
+
+type userSessionRequestBody struct {
+	User userCredentials `json:"user"`
+}
+
+type userCredentials struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	TfaToken string `json:"tfa_token,omitempty"`
+}
+
+// logIn opens a user session and returns its authtoken.
+func logIn(ctx context.Context, c *apiClient, email, password, tfaToken string) (string, error) {
+	endpoint := "/v3/user-session"
+
+	requestBody := userSessionRequestBody{User: userCredentials{Email: email, Password: password, TfaToken: tfaToken}}
+	var r cschema.UserResponse
+	resp, err := newRequest(ctx, c).SetBody(requestBody).SetResult(&r).Post(endpoint)
+	if err := checkResponse(endpoint, http.StatusOK, resp, err); err != nil {
+		return "", err
+	}
+
+	if r.User.Authtoken == "" {
+		return "", fmt.Errorf("calling %#v: no authtoken in response", endpoint)
+	}
+
+	return r.User.Authtoken, nil
+}
+
+// logOut closes the user session whose authtoken the client is using.
+func logOut(ctx context.Context, c *apiClient) error {
+	endpoint := "/v3/user-session"
+
+	resp, err := newRequest(ctx, c).Delete(endpoint)
+	return checkResponse(endpoint, http.StatusOK, resp, err)
+}
+
+// useAuthtoken switches the client from management token to user authtoken
+// authentication.
+func useAuthtoken(c *apiClient, authtoken string) {
+	rc := c.resty
+	rc.Header.Del("Authorization")
+	rc.SetHeader("authtoken", authtoken)
+}
+
+// stackJSON is a Contentstack stack; go-contentstack does not model stacks.
+type stackJSON struct {
+	UID           string                  `json:"uid,omitempty"`
+	Name          string                  `json:"name"`
+	Description   string                  `json:"description"`
+	MasterLocale  string                  `json:"master_locale,omitempty"`
+	OrgUID        string                  `json:"org_uid,omitempty"`
+	APIKey        string                  `json:"api_key,omitempty"`
+	Collaborators []stackCollaboratorJSON `json:"collaborators,omitempty"`
+}
+
+type stackJSONWrapper struct {
+	Stack *stackJSON `json:"stack"`
+}
+
+type stackCollaboratorJSON struct {
+	UID   string   `json:"uid"`
+	Email string   `json:"email"`
+	Roles roleUIDs `json:"roles"`
+}
+
+// roleUIDs decodes a list of roles given either as uids or as role objects.
+type roleUIDs []string
+
+func (r *roleUIDs) UnmarshalJSON(b []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	uids := make(roleUIDs, 0, len(raw))
+	for _, item := range raw {
+		var uid string
+		if err := json.Unmarshal(item, &uid); err == nil {
+			uids = append(uids, uid)
+			continue
+		}
+		var role struct {
+			UID string `json:"uid"`
+		}
+		if err := json.Unmarshal(item, &role); err != nil {
+			return err
+		}
+		uids = append(uids, role.UID)
+	}
+	*r = uids
+
+	return nil
+}
+
+// getStackJSON reads the stack whose api key the client is using.
+func getStackJSON(ctx context.Context, c *apiClient, includeCollaborators bool) (*stackJSON, error) {
+	endpoint := fmt.Sprintf("/v3/stacks?include_collaborators=%t", includeCollaborators)
+
+	var r stackJSONWrapper
+	resp, err := newRequest(ctx, c).SetResult(&r).Get(endpoint)
+	if err := checkResponse(endpoint, http.StatusOK, resp, err); err != nil {
+		return nil, err
+	}
+
+	return r.Stack, nil
+}
+
+func createStackJSON(ctx context.Context, c *apiClient, orgUID string, s *stackJSON) (*stackJSON, error) {
+	endpoint := "/v3/stacks"
+
+	var r stackJSONWrapper
+	resp, err := newRequest(ctx, c).SetHeader("organization_uid", orgUID).SetBody(stackJSONWrapper{Stack: s}).SetResult(&r).Post(endpoint)
+	if err := checkResponse(endpoint, http.StatusCreated, resp, err); err != nil {
+		return nil, err
+	}
+
+	return r.Stack, nil
+}
+
+// updateStackJSON updates the stack whose api key the client is using.
+func updateStackJSON(ctx context.Context, c *apiClient, s *stackJSON) (*stackJSON, error) {
+	endpoint := "/v3/stacks"
+
+	var r stackJSONWrapper
+	resp, err := newRequest(ctx, c).SetBody(stackJSONWrapper{Stack: &stackJSON{Name: s.Name, Description: s.Description}}).SetResult(&r).Put(endpoint)
+	if err := checkResponse(endpoint, http.StatusOK, resp, err); err != nil {
+		return nil, err
+	}
+
+	return r.Stack, nil
+}
+
+// deleteStack deletes the stack whose api key the client is using.
+func deleteStack(ctx context.Context, c *apiClient) error {
+	endpoint := "/v3/stacks"
+
+	resp, err := newRequest(ctx, c).Delete(endpoint)
+	return checkResponse(endpoint, http.StatusOK, resp, err)
+}
+
+type stackShareRequestBody struct {
+	Emails []string            `json:"emails"`
+	Roles  map[string][]string `json:"roles"`
+}
+
+// shareStack invites a user to the stack whose api key the client is using.
+func shareStack(ctx context.Context, c *apiClient, email string, roles []string) error {
+	endpoint := "/v3/stacks/share"
+
+	requestBody := stackShareRequestBody{Emails: []string{email}, Roles: map[string][]string{email: roles}}
+	resp, err := newRequest(ctx, c).SetBody(requestBody).Post(endpoint)
+	return checkResponse(endpoint, http.StatusOK, resp, err)
+}
+
+// unshareStack removes a user from the stack whose api key the client is using.
+func unshareStack(ctx context.Context, c *apiClient, email string) error {
+	endpoint := "/v3/stacks/unshare"
+
+	resp, err := newRequest(ctx, c).SetBody(map[string]string{"email": email}).Post(endpoint)
+	return checkResponse(endpoint, http.StatusOK, resp, err)
+}
+
+type stackUserRolesRequestBody struct {
+	Users map[string][]string `json:"users"`
+}
+
+// updateStackUserRoles replaces the roles of a user of the stack whose api key
+// the client is using.
+func updateStackUserRoles(ctx context.Context, c *apiClient, userUID string, roles []string) error {
+	endpoint := "/v3/stacks/users/roles"
+
+	requestBody := stackUserRolesRequestBody{Users: map[string][]string{userUID: roles}}
+	resp, err := newRequest(ctx, c).SetBody(requestBody).Put(endpoint)
+	return checkResponse(endpoint, http.StatusOK, resp, err)
+}
+
+func getLocales(ctx context.Context, c *apiClient) ([]cschema.Locale, error) {
+	endpoint := "/v3/locales"
+
+	var r management.LocaleListWrapper
+	resp, err := newRequest(ctx, c).SetResult(&r).Get(endpoint)
+	if err := checkResponse(endpoint, http.StatusOK, resp, err); err != nil {
+		return nil, err
+	}
+
+	return r.Locales, nil
+}
+
+func getGlobalField(ctx context.Context, c *apiClient, uid string) (*cschema.GlobalField, error) {
+	endpoint := fmt.Sprintf("/v3/global_fields/%s", uid)
+
+	var r management.GetOneGlobalFieldResponse
+	resp, err := newRequest(ctx, c).SetResult(&r).Get(endpoint)
+	if err := checkResponse(endpoint, http.StatusOK, resp, err); err != nil {
+		return nil, err
+	}
+
+	return r.GlobalField, nil
+}
+
+// deleteGlobalField deletes a GlobalField; unless forced, the API refuses to
+// delete one which content types use.
+func deleteGlobalField(ctx context.Context, c *apiClient, uid string, force bool) error {
+	if uid == "" {
+		return fmt.Errorf("cannot delete a GlobalField without a uid")
+	}
+	endpoint := fmt.Sprintf("/v3/global_fields/%s", uid)
+	if force {
+		endpoint += "?force=true"
+	}
+
+	resp, err := newRequest(ctx, c).Delete(endpoint)
+	return checkResponse(endpoint, http.StatusOK, resp, err)
+}
+
+// contentTypeJSON is a ContentType whose schema is kept as raw JSON, like
+// globalFieldJSON.
+type contentTypeJSON struct {
+	Title       string          `json:"title"`
+	UID         string          `json:"uid"`
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	// Version is only read; the API ignores it on update.
+	Version int `json:"_version,omitempty"`
+}
+
+type contentTypeJSONWrapper struct {
+	ContentType *contentTypeJSON `json:"content_type"`
+}
+
+func getOneContentTypeJSON(ctx context.Context, c *apiClient, uid string) (*contentTypeJSON, error) {
+	endpoint := fmt.Sprintf("/v3/content_types/%s", uid)
+
+	var r contentTypeJSONWrapper
+	resp, err := newRequest(ctx, c).SetResult(&r).Get(endpoint)
+	if err := checkResponse(endpoint, http.StatusOK, resp, err); err != nil {
+		return nil, err
+	}
+
+	return r.ContentType, nil
+}
+
+func createContentTypeJSON(ctx context.Context, c *apiClient, ct *contentTypeJSON) (*contentTypeJSON, error) {
+	endpoint := "/v3/content_types"
+
+	var r contentTypeJSONWrapper
+	resp, err := newRequest(ctx, c).SetBody(contentTypeJSONWrapper{ContentType: ct}).SetResult(&r).Post(endpoint)
+	if err := checkResponse(endpoint, http.StatusCreated, resp, err); err != nil {
+		return nil, err
+	}
+
+	return r.ContentType, nil
+}
+
+func updateContentTypeJSON(ctx context.Context, c *apiClient, ct *contentTypeJSON) (*contentTypeJSON, error) {
+	endpoint := fmt.Sprintf("/v3/content_types/%s", ct.UID)
+
+	var r contentTypeJSONWrapper
+	resp, err := newRequest(ctx, c).SetBody(contentTypeJSONWrapper{ContentType: ct}).SetResult(&r).Put(endpoint)
+	if err := checkResponse(endpoint, http.StatusOK, resp, err); err != nil {
+		return nil, err
+	}
+
+	return r.ContentType, nil
+}
+
+func deleteContentType(ctx context.Context, c *apiClient, uid string) error {
+	if uid == "" {
+		return fmt.Errorf("cannot delete a ContentType without a uid")
+	}
+	endpoint := fmt.Sprintf("/v3/content_types/%s", uid)
+
+	resp, err := newRequest(ctx, c).Delete(endpoint)
+	return checkResponse(endpoint, http.StatusOK, resp, err)
+}
+
+// referenceJSON is a field of a schema which may use a global field or refer
+// to a content type, directly or within its own schema as groups and modular
+// blocks do.
+type referenceJSON struct {
+	DataType    string          `json:"data_type"`
+	ReferenceTo interface{}     `json:"reference_to"`
+	Schema      []referenceJSON `json:"schema"`
+	Blocks      []referenceJSON `json:"blocks"`
+}
+
+// uses reports whether the field is, or contains, the global field uid.
+func (f referenceJSON) uses(uid string) bool {
+	if f.DataType == "global_field" && f.ReferenceTo == uid {
+		return true
+	}
+	for _, nested := range f.Schema {
+		if nested.uses(uid) {
+			return true
+		}
+	}
+	// a block of modular blocks is either a global field, given by
+	// reference_to alone, or has a schema of its own
+	for _, block := range f.Blocks {
+		if block.ReferenceTo == uid || block.uses(uid) {
+			return true
+		}
+	}
+	return false
+}
+
+// references reports whether the field is, or contains, a reference field
+// which may refer to entries of the content type uid.
+func (f referenceJSON) references(uid string) bool {
+	if f.DataType == "reference" {
+		// reference_to is a list of content types, or a single one in the
+		// schemas of older content types
+		switch to := f.ReferenceTo.(type) {
+		case string:
+			if to == uid {
+				return true
+			}
+		case []interface{}:
+			for _, t := range to {
+				if t == uid {
+					return true
+				}
+			}
+		}
+	}
+	for _, nested := range f.Schema {
+		if nested.references(uid) {
+			return true
+		}
+	}
+	for _, block := range f.Blocks {
+		if block.references(uid) {
+			return true
+		}
+	}
+	return false
+}
+
+type contentTypeListJSON struct {
+	ContentTypes []contentTypeJSON `json:"content_types"`
+}
+
+// contentTypesPageSize is the most content types the API returns per request.
+const contentTypesPageSize = 100
+
+// getContentTypesUsingGlobalField returns the content types whose schema
+// includes the GlobalField uid.
+func getContentTypesUsingGlobalField(ctx context.Context, c *apiClient, uid string) ([]contentTypeJSON, error) {
+	return findContentTypes(ctx, c, func(f referenceJSON) bool { return f.uses(uid) })
+}
+
+// getContentTypesReferencing returns the other content types whose schema has
+// a reference field which may refer to entries of the ContentType uid.
+func getContentTypesReferencing(ctx context.Context, c *apiClient, uid string) ([]contentTypeJSON, error) {
+	referencing, err := findContentTypes(ctx, c, func(f referenceJSON) bool { return f.references(uid) })
+	if err != nil {
+		return nil, err
+	}
+
+	others := referencing[:0]
+	for _, ct := range referencing {
+		if ct.UID != uid {
+			others = append(others, ct)
+		}
+	}
+	return others, nil
+}
+
+// findContentTypes returns the content types with a top level field of their
+// schema which matches.
+func findContentTypes(ctx context.Context, c *apiClient, match func(referenceJSON) bool) ([]contentTypeJSON, error) {
+	var found []contentTypeJSON
+
+	for skip := 0; ; skip += contentTypesPageSize {
+		endpoint := fmt.Sprintf("/v3/content_types?limit=%d&skip=%d", contentTypesPageSize, skip)
+
+		var r contentTypeListJSON
+		resp, err := newRequest(ctx, c).SetResult(&r).Get(endpoint)
+		if err := checkResponse(endpoint, http.StatusOK, resp, err); err != nil {
+			return nil, err
+		}
+
+		for _, ct := range r.ContentTypes {
+			var fields []referenceJSON
+			if len(ct.Schema) == 0 {
+				continue
+			}
+			if err := json.Unmarshal(ct.Schema, &fields); err != nil {
+				return nil, fmt.Errorf("parsing the schema of ContentType %#v: %w", ct.UID, err)
+			}
+			for _, f := range fields {
+				if match(f) {
+					found = append(found, ct)
+					break
+				}
+			}
+		}
+
+		if len(r.ContentTypes) < contentTypesPageSize {
+			return found, nil
+		}
+	}
+}
+
+// entryJSON is the part of an entry which identifies it.
+type entryJSON struct {
+	Title string `json:"title"`
+	UID   string `json:"uid"`
+}
+
+type entryListJSON struct {
+	Entries []entryJSON `json:"entries"`
+	Count   int         `json:"count"`
+}
+
+// getSomeEntries returns up to limit entries of the ContentType uid, in the
+// default locale, and how many it has in all.
+func getSomeEntries(ctx context.Context, c *apiClient, uid string, limit int) ([]entryJSON, int, error) {
+	endpoint := fmt.Sprintf("/v3/content_types/%s/entries?include_count=true&limit=%d", uid, limit)
+
+	var r entryListJSON
+	resp, err := newRequest(ctx, c).SetResult(&r).Get(endpoint)
+	if err := checkResponse(endpoint, http.StatusOK, resp, err); err != nil {
+		return nil, 0, err
+	}
+
+	return r.Entries, r.Count, nil
+}
+
+// getEnvironment reads an Environment by its name, which is what the API
+// addresses environments by.
+func getEnvironment(ctx context.Context, c *apiClient, name string) (*cschema.Environment, error) {
+	endpoint := fmt.Sprintf("/v3/environments/%s", url.PathEscape(name))
+
+	var r cschema.SingleEnvironmentWrapper
+	resp, err := newRequest(ctx, c).SetResult(&r).Get(endpoint)
+	if err := checkResponse(endpoint, http.StatusOK, resp, err); err != nil {
+		return nil, err
+	}
+
+	return &r.Environment, nil
+}
+
+func getEnvironments(ctx context.Context, c *apiClient) ([]cschema.Environment, error) {
+	endpoint := "/v3/environments"
+
+	var r cschema.EnvironmentListWrapper
+	resp, err := newRequest(ctx, c).SetResult(&r).Get(endpoint)
+	if err := checkResponse(endpoint, http.StatusOK, resp, err); err != nil {
+		return nil, err
+	}
+
+	return r.Environments, nil
+}
+
+// findEnvironment looks an Environment up by its uid, which the API cannot
+// address it by, or else by its name; it returns nil when there is none.
+func findEnvironment(ctx context.Context, c *apiClient, uidOrName string) (*cschema.Environment, error) {
+	environments, err := getEnvironments(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range environments {
+		if environments[i].UID == uidOrName {
+			return &environments[i], nil
+		}
+	}
+	for i := range environments {
+		if environments[i].Name == uidOrName {
+			return &environments[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func createEnvironment(ctx context.Context, c *apiClient, e *cschema.Environment) (*cschema.Environment, error) {
+	endpoint := "/v3/environments"
+
+	var r cschema.SingleEnvironmentWrapper
+	resp, err := newRequest(ctx, c).SetBody(cschema.SingleEnvironmentWrapper{Environment: *e}).SetResult(&r).Post(endpoint)
+	if err := checkResponse(endpoint, http.StatusCreated, resp, err); err != nil {
+		return nil, err
+	}
+
+	return &r.Environment, nil
+}
+
+// updateEnvironment updates the Environment currently named name, so that its
+// name can be changed in place.
+func updateEnvironment(ctx context.Context, c *apiClient, name string, e *cschema.Environment) (*cschema.Environment, error) {
+	endpoint := fmt.Sprintf("/v3/environments/%s", url.PathEscape(name))
+
+	var r cschema.UpsertEnvironmentResponse
+	resp, err := newRequest(ctx, c).SetBody(cschema.SingleEnvironmentWrapper{Environment: *e}).SetResult(&r).Put(endpoint)
+	if err := checkResponse(endpoint, http.StatusOK, resp, err); err != nil {
+		return nil, err
+	}
+
+	return &r.Environment, nil
+}
+
+func deleteEnvironment(ctx context.Context, c *apiClient, name string) error {
+	endpoint := fmt.Sprintf("/v3/environments/%s", url.PathEscape(name))
+
+	resp, err := newRequest(ctx, c).Delete(endpoint)
+	return checkResponse(endpoint, http.StatusOK, resp, err)
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davidalpert/go-contentstack/v1/management"
+)
+
+func TestRequestsHonorContextCancellation(t *testing.T) {
+	t.Parallel()
+
+	// a hung API which only returns once the client goes away
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := newAPIClient(&management.Configuration{Host: server.URL, Key: "api-key", Token: "management-token"})
+	useRetries(client, retryPolicy{MaxRetries: 5, MinWait: time.Second, MaxWait: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := getLocales(ctx, client)
+	if err == nil {
+		t.Fatal("expected error, got no error")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline exceeded error, got: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the request to stop with its context, took %s", elapsed)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := newAPIClient(&management.Configuration{Host: server.URL, Key: "api-key", Token: "management-token"})
+	client.resty.SetTimeout(20 * time.Millisecond)
+
+	if _, err := getLocales(context.Background(), client); err == nil {
+		t.Fatal("expected error, got no error")
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"environment not found", newAPIError("/v3/environments/staging", 422, "422 Unprocessable Entity", []byte(`{"error_message":"Environment was not found.","error_code":141}`)), true},
+		{"global field not found", newAPIError("/v3/global_fields/seo", 422, "422 Unprocessable Entity", []byte(`{"error_message":"Global Field was not found.","error_code":118}`)), true},
+		{"http not found", newAPIError("/v3/global_fields/seo", 404, "404 Not Found", []byte(`not found`)), true},
+		{"validation error", newAPIError("/v3/global_fields", 422, "422 Unprocessable Entity", []byte(`{"error_message":"Global Field creation failed.","error_code":119}`)), false},
+		{"wrapped", fmt.Errorf("reading: %w", newAPIError("/v3/environments/staging", 422, "422 Unprocessable Entity", []byte(`{"error_code":141}`))), true},
+		{"not an api error", errors.New("connection refused"), false},
+		{"no error", nil, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isNotFound(tt.err); got != tt.want {
+				t.Errorf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReferenceUses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		schema string
+		want   bool
+	}{
+		{"global field", `[{"uid":"seo","data_type":"global_field","reference_to":"seo"}]`, true},
+		{"other global field", `[{"uid":"meta","data_type":"global_field","reference_to":"meta"}]`, false},
+		{"reference field", `[{"uid":"author","data_type":"reference","reference_to":["seo"]}]`, false},
+		{"in a group", `[{"uid":"meta","data_type":"group","schema":[{"uid":"seo","data_type":"global_field","reference_to":"seo"}]}]`, true},
+		{"global field block", `[{"uid":"sections","data_type":"blocks","blocks":[{"uid":"seo","title":"SEO","reference_to":"seo"}]}]`, true},
+		{"in a block", `[{"uid":"sections","data_type":"blocks","blocks":[{"uid":"hero","schema":[{"uid":"seo","data_type":"global_field","reference_to":"seo"}]}]}]`, true},
+		{"text", `[{"uid":"title","data_type":"text"}]`, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var fields []referenceJSON
+			if err := json.Unmarshal([]byte(tt.schema), &fields); err != nil {
+				t.Fatalf("got unexpected error: %s", err)
+			}
+
+			got := false
+			for _, f := range fields {
+				got = got || f.uses("seo")
+			}
+			if got != tt.want {
+				t.Errorf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReferenceReferences(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		schema string
+		want   bool
+	}{
+		{"reference field", `[{"uid":"authors","data_type":"reference","reference_to":["author"]}]`, true},
+		{"one of several", `[{"uid":"related","data_type":"reference","reference_to":["blog_post","author"]}]`, true},
+		{"single content type", `[{"uid":"author","data_type":"reference","reference_to":"author"}]`, true},
+		{"other content type", `[{"uid":"related","data_type":"reference","reference_to":["blog_post"]}]`, false},
+		{"global field", `[{"uid":"author","data_type":"global_field","reference_to":"author"}]`, false},
+		{"in a group", `[{"uid":"meta","data_type":"group","schema":[{"uid":"authors","data_type":"reference","reference_to":["author"]}]}]`, true},
+		{"in a block", `[{"uid":"sections","data_type":"blocks","blocks":[{"uid":"byline","schema":[{"uid":"authors","data_type":"reference","reference_to":["author"]}]}]}]`, true},
+		{"text", `[{"uid":"author","data_type":"text"}]`, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var fields []referenceJSON
+			if err := json.Unmarshal([]byte(tt.schema), &fields); err != nil {
+				t.Fatalf("got unexpected error: %s", err)
+			}
+
+			got := false
+			for _, f := range fields {
+				got = got || f.references("author")
+			}
+			if got != tt.want {
+				t.Errorf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
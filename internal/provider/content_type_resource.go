@@ -0,0 +1,487 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/davidalpert/terraform-provider-contentstack/internal/tfutils/schemajson"
+	mystringplanmodifiers "github.com/davidalpert/terraform-provider-contentstack/internal/tfutils/stringplanmodifiers"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ContentTypeResource{}
+var _ resource.ResourceWithImportState = &ContentTypeResource{}
+var _ resource.ResourceWithModifyPlan = &ContentTypeResource{}
+
+func NewContentTypeResource() resource.Resource {
+	return &ContentTypeResource{}
+}
+
+// ContentTypeResource defines the resource implementation.
+type ContentTypeResource struct {
+	clients *clientFactory
+}
+
+// ContentTypeResourceModel describes the resource data model.
+type ContentTypeResourceModel struct {
+	AllowDestructiveChanges types.Bool       `tfsdk:"allow_destructive_changes"`
+	DeletionProtection      types.Bool       `tfsdk:"deletion_protection"`
+	Description             types.String     `tfsdk:"description"`
+	ForceDelete             types.Bool       `tfsdk:"force_delete"`
+	ID                      types.String     `tfsdk:"id"`
+	SchemaJSON              schemajson.Value `tfsdk:"schema_json"`
+	StackAPIKey             types.String     `tfsdk:"stack_api_key"`
+	Title                   types.String     `tfsdk:"title"`
+	UID                     types.String     `tfsdk:"uid"`
+	Version                 types.Int64      `tfsdk:"version"`
+	Timeouts                timeouts.Value   `tfsdk:"timeouts"`
+}
+
+// entriesListedInDiagnostics is how many entries of a ContentType the error
+// refusing to delete it lists.
+const entriesListedInDiagnostics = 10
+
+func (data *ContentTypeResourceModel) Update(ct *contentTypeJSON) {
+	data.Description = types.StringValue(ct.Description)
+	data.ID = types.StringValue(ct.UID)
+	data.SchemaJSON = schemajson.NewValue(string(ct.Schema))
+	data.Title = types.StringValue(ct.Title)
+	data.UID = types.StringValue(ct.UID)
+	data.Version = types.Int64Value(int64(ct.Version))
+}
+
+func (data *ContentTypeResourceModel) Export() *contentTypeJSON {
+	return &contentTypeJSON{
+		Description: data.Description.ValueString(),
+		Schema:      json.RawMessage(data.SchemaJSON.ValueString()),
+		Title:       data.Title.ValueString(),
+		UID:         data.UID.ValueString(),
+	}
+}
+
+// errorPath maps a property of a ContentType request, such as
+// "schema.3.uid", to the attribute which sets it.
+func (data *ContentTypeResourceModel) errorPath(key string) (path.Path, bool) {
+	first, _, _ := splitErrorKey(key)
+
+	switch first {
+	case "description", "title", "uid":
+		return path.Root(first), true
+	case "schema":
+		return path.Root("schema_json"), true
+	}
+
+	return path.Empty(), false
+}
+
+func (r *ContentTypeResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_content_type"
+}
+
+func (r *ContentTypeResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "ContentType resource",
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+
+		Attributes: map[string]schema.Attribute{
+			"allow_destructive_changes": schema.BoolAttribute{
+				MarkdownDescription: "apply changes to fields which lose or invalidate the data of existing entries, i.e. removing a field, changing its `data_type`, turning on `unique` or `mandatory` or turning off `multiple`, with a warning rather than failing the plan. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "refuse to delete the ContentType while it has entries or other content types have reference fields to it, as deleting it also deletes its entries; the error lists them. Defaults to true. Like `force_delete`, it only takes effect once applied, so turn it off in an apply before the one which destroys the ContentType.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "description of the ContentType",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					mystringplanmodifiers.DefaultValue(""),
+				},
+			},
+			"force_delete": schema.BoolAttribute{
+				MarkdownDescription: "delete the ContentType even though it has entries or is referenced, overriding `deletion_protection`; its entries are deleted with it. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "ContentType identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"schema_json": schema.StringAttribute{
+				CustomType:          schemajson.Type{},
+				MarkdownDescription: "field schema of the ContentType as a Contentstack schema JSON array, such as the schema of an exported content type, used verbatim. Key order and properties which the server fills in with their defaults are ignored when comparing.",
+				Required:            true,
+			},
+			"stack_api_key": schema.StringAttribute{
+				MarkdownDescription: stackAPIKeyDescription,
+				Optional:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"title": schema.StringAttribute{
+				MarkdownDescription: "title of the ContentType",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					mystringplanmodifiers.DefaultValueCopiedFromAnotherField("uid"),
+				},
+			},
+			"uid": schema.StringAttribute{
+				MarkdownDescription: "uid of the ContentType",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.Int64Attribute{
+				MarkdownDescription: "version number of the ContentType",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *ContentTypeResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	clients, ok := req.ProviderData.(*clientFactory)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *clientFactory, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.clients = clients
+}
+
+func (r *ContentTypeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *ContentTypeResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, dg := data.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	created, err := createContentTypeJSON(ctx, client, data.Export())
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to create ContentType %#v", data.UID.ValueString()), err, data.errorPath)
+		return
+	}
+
+	// explicitly save the computed fields; a schema_json which the server only
+	// decorated with defaults is kept as planned by its semantic equality
+	data.Update(created)
+
+	tflog.Trace(ctx, "created a ContentType", map[string]interface{}{
+		"uid": created.UID,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ContentTypeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *ContentTypeResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, dg := data.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ct, err := getOneContentTypeJSON(ctx, client, data.ID.ValueString())
+	if isNotFound(err) {
+		// deleted outside Terraform; planning to create it again
+		tflog.Warn(ctx, "ContentType not found, removing it from state", map[string]interface{}{
+			"uid": data.UID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to read ContentType %#v", data.UID.ValueString()), err, nil)
+		return
+	}
+
+	data.Update(ct)
+
+	// settings of the resource rather than of the ContentType, which are
+	// unset after an import
+	if data.AllowDestructiveChanges.IsNull() {
+		data.AllowDestructiveChanges = types.BoolValue(false)
+	}
+	if data.DeletionProtection.IsNull() {
+		data.DeletionProtection = types.BoolValue(true)
+	}
+	if data.ForceDelete.IsNull() {
+		data.ForceDelete = types.BoolValue(false)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ContentTypeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *ContentTypeResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, dg := data.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.clients.strictVersioning {
+		var version types.Int64
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("version"), &version)...)
+
+		current, err := getOneContentTypeJSON(ctx, client, data.ID.ValueString())
+		if err != nil {
+			addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to read ContentType %#v", data.ID.ValueString()), err, nil)
+			return
+		}
+
+		resp.Diagnostics.Append(checkVersion("ContentType", data.ID.ValueString(), version, current.Version)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	updated, err := updateContentTypeJSON(ctx, client, data.Export())
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to update ContentType %#v", data.ID.ValueString()), err, data.errorPath)
+		return
+	}
+
+	data.Update(updated)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ContentTypeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *ContentTypeResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, dg := data.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.DeletionProtection.ValueBool() && !data.ForceDelete.ValueBool() {
+		resp.Diagnostics.Append(checkContentTypeUnused(ctx, client, data.ID.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	err := deleteContentType(ctx, client, data.ID.ValueString())
+	if isNotFound(err) {
+		// already deleted outside Terraform
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to delete ContentType %#v", data.ID.ValueString()), err, nil)
+		return
+	}
+}
+
+// checkContentTypeUnused fails when the ContentType uid has entries, which
+// deleting it would delete, or other content types refer to it.
+func checkContentTypeUnused(ctx context.Context, client *apiClient, uid string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	entries, count, err := getSomeEntries(ctx, client, uid, entriesListedInDiagnostics)
+	if err != nil {
+		addClientError(&diags, fmt.Sprintf("Unable to find the entries of ContentType %#v", uid), err, nil)
+		return diags
+	}
+
+	contentTypes, err := getContentTypesReferencing(ctx, client, uid)
+	if err != nil {
+		addClientError(&diags, fmt.Sprintf("Unable to find the content types referencing ContentType %#v", uid), err, nil)
+		return diags
+	}
+
+	var uses []string
+	if count > 0 {
+		names := make([]string, len(entries))
+		for i, e := range entries {
+			names[i] = fmt.Sprintf("%#v (%s)", e.UID, e.Title)
+		}
+		if count > len(entries) {
+			names = append(names, fmt.Sprintf("and %d more", count-len(entries)))
+		}
+		uses = append(uses, fmt.Sprintf("has the entries %s", strings.Join(names, ", ")))
+	}
+	if len(contentTypes) > 0 {
+		names := make([]string, len(contentTypes))
+		for i, ct := range contentTypes {
+			names[i] = fmt.Sprintf("%#v (%s)", ct.UID, ct.Title)
+		}
+		uses = append(uses, fmt.Sprintf("is referenced by the content types %s", strings.Join(names, ", ")))
+	}
+
+	if len(uses) > 0 {
+		diags.AddAttributeError(
+			path.Root("deletion_protection"),
+			"ContentType In Use",
+			fmt.Sprintf("ContentType %#v is protected from deletion and %s; deleting it would delete its entries and break the references to them. "+
+				"Delete those entries and references first, or apply force_delete = true before destroying it.",
+				uid, strings.Join(uses, " and ")),
+		)
+	}
+
+	return diags
+}
+
+func (r *ContentTypeResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		checkContentTypeDestructiveChanges(ctx, req, resp)
+	}
+
+	// nor before the provider is configured
+	if resp.Diagnostics.HasError() || r.clients == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(r.clients.ValidatePlan(ctx, req.Plan)...)
+}
+
+// checkContentTypeDestructiveChanges reports the planned changes to the
+// fields of the schema_json which lose or invalidate the data of existing
+// entries, like checkDestructiveChanges does for a GlobalField.
+func checkContentTypeDestructiveChanges(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var allow types.Bool
+	var priorSchemaJSON, plannedSchemaJSON schemajson.Value
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("allow_destructive_changes"), &allow)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("schema_json"), &priorSchemaJSON)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("schema_json"), &plannedSchemaJSON)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the planned schema_json is unknown when built from other resources
+	prior, ok := summarizeSchemaJSON(priorSchemaJSON)
+	if !ok {
+		return
+	}
+	planned, ok := summarizeSchemaJSON(plannedSchemaJSON)
+	if !ok {
+		return
+	}
+
+	fieldPath := func(destructiveChange) path.Path {
+		return path.Root("schema_json")
+	}
+	reportDestructiveChanges(&resp.Diagnostics, "ContentType", allow.ValueBool(), destructiveChanges(prior, planned), fieldPath)
+}
+
+func (r *ContentTypeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStatePassthroughStackScopedID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,306 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/davidalpert/terraform-provider-contentstack/internal/contentstacktest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func testAccContentTypeResourceConfig(api *contentstacktest.Server, body string) string {
+	return testAccProviderConfig(api) + fmt.Sprintf(`
+resource "contentstack_content_type" "test" {
+%s
+}
+`, body)
+}
+
+// testAccCheckContentTypeDestroyed checks that destroying the configuration
+// deleted the content type from the stack.
+func testAccCheckContentTypeDestroyed(api *contentstacktest.Server, uid string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if _, ok := api.Get(contentstacktest.ContentTypes, uid); ok {
+			return fmt.Errorf("expected ContentType %#v to be deleted", uid)
+		}
+		return nil
+	}
+}
+
+func TestAccContentTypeResource(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	config := func(description string) string {
+		return testAccContentTypeResourceConfig(api, fmt.Sprintf(`
+  uid         = "blog_post"
+  title       = "Blog Post"
+  description = %q
+  schema_json = jsonencode([
+    {
+      uid          = "title"
+      display_name = "Title"
+      data_type    = "text"
+      mandatory    = true
+      unique       = true
+    },
+    {
+      uid          = "seo"
+      display_name = "SEO"
+      data_type    = "global_field"
+      reference_to = "seo"
+    }
+  ])
+`, description))
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckContentTypeDestroyed(api, "blog_post"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: config("created by terraform"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_content_type.test", "id", "blog_post"),
+					resource.TestCheckResourceAttr("contentstack_content_type.test", "title", "Blog Post"),
+					resource.TestCheckResourceAttr("contentstack_content_type.test", "version", "1"),
+					func(s *terraform.State) error {
+						r, _ := api.Get(contentstacktest.ContentTypes, "blog_post")
+						fields, _ := r["schema"].([]interface{})
+						if len(fields) != 2 {
+							return fmt.Errorf("expected the schema to be sent verbatim, got %v", r["schema"])
+						}
+						return nil
+					},
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "contentstack_content_type.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// the server decorating the schema with defaults is not a change, though
+			// it bumps the version
+			{
+				PreConfig: func() {
+					api.Modify(contentstacktest.ContentTypes, "blog_post", func(r contentstacktest.Record) {
+						fields, _ := r["schema"].([]interface{})
+						f, _ := fields[1].(map[string]interface{})
+						f["mandatory"] = false
+						f["multiple"] = false
+					})
+				},
+				Config:   config("created by terraform"),
+				PlanOnly: true,
+			},
+			// Update and Read testing
+			{
+				Config: config("updated by terraform"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_content_type.test", plancheck.ResourceActionUpdate),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_content_type.test", "description", "updated by terraform"),
+					resource.TestCheckResourceAttr("contentstack_content_type.test", "version", "3"),
+				),
+			},
+			// a content type deleted in the UI is planned to be created again
+			{
+				PreConfig: func() {
+					api.Remove(contentstacktest.ContentTypes, "blog_post")
+				},
+				Config: config("updated by terraform"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_content_type.test", plancheck.ResourceActionCreate),
+					},
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccContentTypeResourceDeletionProtection(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	config := func(settings string) string {
+		return testAccContentTypeResourceConfig(api, `
+  uid         = "author"
+  title       = "Author"
+  schema_json = jsonencode([
+    {
+      uid          = "title"
+      display_name = "Name"
+      data_type    = "text"
+    }
+  ])
+`+settings)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckContentTypeDestroyed(api, "author"),
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_content_type.test", "deletion_protection", "true"),
+					resource.TestCheckResourceAttr("contentstack_content_type.test", "force_delete", "false"),
+					func(s *terraform.State) error {
+						api.AddEntry("author", contentstacktest.Record{"title": "Jane Doe"})
+						api.Add(contentstacktest.ContentTypes, contentstacktest.Record{
+							"uid":   "blog_post",
+							"title": "Blog Post",
+							"schema": []interface{}{
+								map[string]interface{}{"uid": "authors", "data_type": "reference", "reference_to": []interface{}{"author"}},
+							},
+						})
+						return nil
+					},
+				),
+			},
+			// a protected content type with entries or references is not deleted
+			{
+				Config:      testAccProviderConfig(api),
+				ExpectError: regexp.MustCompile(`(?s)ContentType In Use.*entries\s+"blt[0-9a-f]+" \(Jane Doe\).*content types\s+"blog_post" \(Blog Post\)`),
+			},
+			// unless forced
+			{
+				Config: config(`
+  force_delete = true
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_content_type.test", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			{
+				Config: testAccProviderConfig(api),
+			},
+		},
+	})
+}
+
+func TestAccContentTypeResourceWithoutDeletionProtection(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckContentTypeDestroyed(api, "author"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccContentTypeResourceConfig(api, `
+  uid                 = "author"
+  deletion_protection = false
+  schema_json = jsonencode([
+    {
+      uid          = "title"
+      display_name = "Name"
+      data_type    = "text"
+    }
+  ])
+`),
+				Check: func(s *terraform.State) error {
+					api.AddEntry("author", contentstacktest.Record{"title": "Jane Doe"})
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccContentTypeResourceDestructiveChanges(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	created := testAccContentTypeResourceConfig(api, `
+  uid         = "blog_post"
+  schema_json = jsonencode([
+    {
+      uid          = "title"
+      display_name = "Title"
+      data_type    = "text"
+    },
+    {
+      uid          = "body"
+      display_name = "Body"
+      data_type    = "text"
+    },
+    {
+      uid          = "summary"
+      display_name = "Summary"
+      data_type    = "text"
+    }
+  ])
+`)
+	changed := func(settings string) string {
+		return testAccContentTypeResourceConfig(api, `
+  uid         = "blog_post"
+  schema_json = jsonencode([
+    {
+      uid          = "title"
+      display_name = "Title"
+      data_type    = "text"
+      mandatory    = true
+      unique       = true
+    },
+    {
+      uid          = "body"
+      display_name = "Body"
+      data_type    = "json"
+    }
+  ])
+`+settings)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckContentTypeDestroyed(api, "blog_post"),
+		Steps: []resource.TestStep{
+			{
+				Config: created,
+				Check:  resource.TestCheckResourceAttr("contentstack_content_type.test", "allow_destructive_changes", "false"),
+			},
+			// changes which lose the data of entries fail the plan
+			{
+				Config: changed(""),
+				ExpectError: regexp.MustCompile(`(?s)Destructive Schema Change.*removing field "summary"` +
+					`.*Destructive Schema Change.*making field "title" unique` +
+					`.*Destructive Schema Change.*making field "title" mandatory` +
+					`.*Destructive Schema Change.*data_type of field "body" from "text" to "json"`),
+			},
+			// unless allowed
+			{
+				Config: changed(`
+  allow_destructive_changes = true
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_content_type.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_content_type.test", "allow_destructive_changes", "true"),
+					func(s *terraform.State) error {
+						r, _ := api.Get(contentstacktest.ContentTypes, "blog_post")
+						fields, _ := r["schema"].([]interface{})
+						if len(fields) != 2 {
+							return fmt.Errorf("expected the changed schema to be applied, got %v", r["schema"])
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
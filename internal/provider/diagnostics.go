@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"strconv"
+	"strings"
+)
+
+// errorPathFunc maps a property of an API request, such as "schema.3.uid", to
+// the attribute which sets it.
+type errorPathFunc func(key string) (path.Path, bool)
+
+// addClientError reports a failed API call; detail says what failed, e.g.
+// `Unable to create GlobalField "seo"`.
+//
+// An error response of the API is reported with its error code in the summary
+// and one attribute diagnostic for each offending property which errorPath
+// maps to an attribute, so that Terraform points at the configuration to fix.
+// errorPath may be nil when no property maps to an attribute.
+func addClientError(diags *diag.Diagnostics, detail string, err error, errorPath errorPathFunc) {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.Message == "" {
+		diags.AddError("Client Error", fmt.Sprintf("%s, got error: %s", detail, err))
+		return
+	}
+
+	summary := "Client Error"
+	if apiErr.Code != 0 {
+		summary = fmt.Sprintf("Client Error (error code %d)", apiErr.Code)
+	}
+
+	var unmapped []string
+	for _, key := range apiErr.ErrorKeys() {
+		if errorPath != nil {
+			if p, ok := errorPath(key); ok {
+				diags.AddAttributeError(p, summary, fmt.Sprintf("%s, got error: %s %s", detail, apiErr.Message, apiErr.PropertyError(key)))
+				continue
+			}
+		}
+		unmapped = append(unmapped, apiErr.PropertyError(key))
+	}
+
+	if len(unmapped) == 0 && len(apiErr.Errors) > 0 {
+		return
+	}
+
+	msg := apiErr.Message
+	if len(unmapped) > 0 {
+		msg += " " + strings.Join(unmapped, " ")
+	}
+	diags.AddError(summary, fmt.Sprintf("%s, got error: %s", detail, msg))
+}
+
+// errorPathToAttributes maps the top level properties of a request, and
+// anything nested under them, to the attributes of the same names.
+func errorPathToAttributes(names ...string) errorPathFunc {
+	return func(key string) (path.Path, bool) {
+		first, _, _ := splitErrorKey(key)
+		for _, name := range names {
+			if first == name {
+				return path.Root(name), true
+			}
+		}
+		return path.Empty(), false
+	}
+}
+
+// splitErrorKey splits a property such as "schema.3.uid" into its first
+// segment, the list index which follows it if any, and the rest.
+func splitErrorKey(key string) (first string, index int, rest string) {
+	first, rest, _ = strings.Cut(key, ".")
+	index = -1
+
+	segment, after, _ := strings.Cut(rest, ".")
+	if i, err := strconv.Atoi(segment); err == nil && i >= 0 {
+		index = i
+		rest = after
+	}
+
+	return first, index, rest
+}
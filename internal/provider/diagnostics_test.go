@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/davidalpert/terraform-provider-contentstack/internal/tfutils/schemajson"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNewAPIError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantCode   int
+		wantErrors map[string][]string
+		wantError  string
+	}{
+		{
+			name:       "errors by property",
+			body:       `{"error_message":"Global Field creation failed. Please try again.","error_code":119,"errors":{"title":["can't be blank."],"schema.1.uid":"is not unique.","schema.2":{"x":1}}}`,
+			wantCode:   119,
+			wantErrors: map[string][]string{"title": {"can't be blank."}, "schema.1.uid": {"is not unique."}, "schema.2": {`{"x":1}`}},
+			wantError:  `calling "/v3/global_fields"  422 Unprocessable Entity: Global Field creation failed. Please try again. schema.1.uid is not unique. schema.2 {"x":1} title can't be blank. (error code 119)`,
+		},
+		{
+			name:      "message only",
+			body:      `{"error_message":"Environment was not found.","error_code":141}`,
+			wantCode:  141,
+			wantError: `calling "/v3/global_fields"  422 Unprocessable Entity: Environment was not found. (error code 141)`,
+		},
+		{
+			name:      "not json",
+			body:      `<html>bad gateway</html>`,
+			wantError: `calling "/v3/global_fields"  422 Unprocessable Entity: <html>bad gateway</html>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := newAPIError("/v3/global_fields", http.StatusUnprocessableEntity, "422 Unprocessable Entity", []byte(tt.body))
+			if e.Code != tt.wantCode {
+				t.Errorf("expected code %d, got %d", tt.wantCode, e.Code)
+			}
+			if len(e.Errors) != len(tt.wantErrors) {
+				t.Errorf("expected errors %v, got %v", tt.wantErrors, e.Errors)
+			}
+			for key, want := range tt.wantErrors {
+				if got := e.Errors[key]; len(got) != len(want) || got[0] != want[0] {
+					t.Errorf("expected errors[%s] to be %v, got %v", key, want, got)
+				}
+			}
+			if e.Error() != tt.wantError {
+				t.Errorf("expected error %q, got %q", tt.wantError, e.Error())
+			}
+		})
+	}
+}
+
+func TestAddClientError(t *testing.T) {
+	t.Parallel()
+
+	apiErr := newAPIError("/v3/environments", http.StatusUnprocessableEntity, "422 Unprocessable Entity",
+		[]byte(`{"error_message":"Environment creation failed. Please try again.","error_code":119,"errors":{"name":["is not unique."],"servers":["are not valid."]}}`))
+
+	tests := []struct {
+		name      string
+		err       error
+		errorPath errorPathFunc
+		want      diag.Diagnostics
+	}{
+		{
+			name: "not an api error",
+			err:  errors.New("connection refused"),
+			want: diag.Diagnostics{
+				diag.NewErrorDiagnostic("Client Error", `Unable to create Environment "staging", got error: connection refused`),
+			},
+		},
+		{
+			name:      "mapped and unmapped properties",
+			err:       apiErr,
+			errorPath: environmentErrorPath,
+			want: diag.Diagnostics{
+				diag.NewAttributeErrorDiagnostic(path.Root("name"), "Client Error (error code 119)", `Unable to create Environment "staging", got error: Environment creation failed. Please try again. name is not unique.`),
+				diag.NewErrorDiagnostic("Client Error (error code 119)", `Unable to create Environment "staging", got error: Environment creation failed. Please try again. servers are not valid.`),
+			},
+		},
+		{
+			name: "no error path",
+			err:  apiErr,
+			want: diag.Diagnostics{
+				diag.NewErrorDiagnostic("Client Error (error code 119)", `Unable to create Environment "staging", got error: Environment creation failed. Please try again. name is not unique. servers are not valid.`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var diags diag.Diagnostics
+			addClientError(&diags, `Unable to create Environment "staging"`, tt.err, tt.errorPath)
+
+			if !diags.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, diags)
+			}
+		})
+	}
+}
+
+func TestGlobalFieldErrorPath(t *testing.T) {
+	t.Parallel()
+
+	withFields := &GlobalFieldResourceModel{
+		SchemaJSON: schemajson.NullValue(),
+		Fields: []GlobalFieldSchemaFieldResourceModel{
+			{Uid: types.StringValue("title_text")},
+			{Uid: types.StringValue("keywords")},
+		},
+	}
+	withSchemaJSON := &GlobalFieldResourceModel{SchemaJSON: schemajson.NewValue(`[]`)}
+
+	tests := []struct {
+		name   string
+		data   *GlobalFieldResourceModel
+		key    string
+		want   path.Path
+		wantOK bool
+	}{
+		{"title", withFields, "title", path.Root("title"), true},
+		{"field attribute", withFields, "schema.1.uid", path.Root("fields").AtListIndex(1).AtName("uid"), true},
+		{"field metadata", withFields, "schema.0.field_metadata.placeholder", path.Root("fields").AtListIndex(0).AtName("placeholder"), true},
+		{"unknown field property", withFields, "schema.1.reference_to", path.Root("fields").AtListIndex(1), true},
+		{"field out of range", withFields, "schema.5.uid", path.Root("fields"), true},
+		{"schema", withFields, "schema", path.Root("fields"), true},
+		{"schema json", withSchemaJSON, "schema.1.uid", path.Root("schema_json"), true},
+		{"unknown property", withFields, "maintain_revisions", path.Empty(), false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := tt.data.errorPath(tt.key)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("expected %s (%t), got %s (%t)", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"fmt"
-	"github.com/davidalpert/go-contentstack/v1/management"
 	cschema "github.com/davidalpert/go-contentstack/v1/schema"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -22,7 +21,7 @@ func NewEnvironmentDataSource() datasource.DataSource {
 
 // EnvironmentDataSource defines the data source implementation.
 type EnvironmentDataSource struct {
-	client *management.Client
+	clients *clientFactory
 }
 
 // EnvironmentDataSourceModel describes the data source data model.
@@ -32,9 +31,12 @@ type EnvironmentDataSourceModel struct {
 	ID            types.String `tfsdk:"id"`
 	UID           types.String `tfsdk:"uid"`
 	CreatedAt     types.String `tfsdk:"created_at"`
+	CreatedBy     types.String `tfsdk:"created_by"`
 	UpdatedAt     types.String `tfsdk:"updated_at"`
+	UpdatedBy     types.String `tfsdk:"updated_by"`
 	Version       types.Int64  `tfsdk:"version"`
 	DeployContent types.Bool   `tfsdk:"deploy_content"`
+	StackAPIKey   types.String `tfsdk:"stack_api_key"`
 }
 
 func (d *EnvironmentDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -60,11 +62,19 @@ func (d *EnvironmentDataSource) Schema(ctx context.Context, req datasource.Schem
 				Required:            true,
 			},
 			"created_at": schema.StringAttribute{
-				MarkdownDescription: "created_at of the Global Field",
+				MarkdownDescription: "time the Environment was created, in RFC 3339 format",
+				Computed:            true,
+			},
+			"created_by": schema.StringAttribute{
+				MarkdownDescription: "uid of the user who created the Environment",
 				Computed:            true,
 			},
 			"updated_at": schema.StringAttribute{
-				MarkdownDescription: "updated_at of the Global Field",
+				MarkdownDescription: "time the Environment was last changed, in RFC 3339 format",
+				Computed:            true,
+			},
+			"updated_by": schema.StringAttribute{
+				MarkdownDescription: "uid of the user who last changed the Environment",
 				Computed:            true,
 			},
 			"version": schema.Int64Attribute{
@@ -80,6 +90,11 @@ func (d *EnvironmentDataSource) Schema(ctx context.Context, req datasource.Schem
 				Computed:            true,
 				MarkdownDescription: "urls by locale",
 			},
+			"stack_api_key": schema.StringAttribute{
+				MarkdownDescription: stackAPIKeyDescription,
+				Optional:            true,
+				Sensitive:           true,
+			},
 		},
 	}
 }
@@ -90,18 +105,18 @@ func (d *EnvironmentDataSource) Configure(ctx context.Context, req datasource.Co
 		return
 	}
 
-	client, ok := req.ProviderData.(*management.Client)
+	clients, ok := req.ProviderData.(*clientFactory)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *management.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *clientFactory, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client
+	d.clients = clients
 }
 
 func (d *EnvironmentDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -114,19 +129,25 @@ func (d *EnvironmentDataSource) Read(ctx context.Context, req datasource.ReadReq
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	g, err := d.client.GetOnePublishingEnvironment(data.Name.ValueString())
+	client, dg := d.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	g, err := getEnvironment(ctx, client, data.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read Environment %#v, got error: %s", data.UID.ValueString(), err))
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to read Environment %#v", data.UID.ValueString()), err, nil)
 		return
 	}
 
 	data.Name = types.StringValue(g.Name)
 	data.UID = types.StringValue(g.UID)
 	data.Version = types.Int64Value(int64(g.Version))
-	data.CreatedAt = types.StringValue("TBD")
-	data.UpdatedAt = types.StringValue("TBD")
+	data.CreatedAt = timestampValue(g.CreatedAt)
+	data.CreatedBy = types.StringValue(g.CreatedBy)
+	data.UpdatedAt = timestampValue(g.UpdatedAt)
+	data.UpdatedBy = types.StringValue(g.UpdatedBy)
 	data.DeployContent = types.BoolValue(g.DeployContent)
 
 	urls, dg := flattenUrlsByLocale(g.Urls)
@@ -3,22 +3,25 @@ package provider
 import (
 	"context"
 	"fmt"
-	"github.com/davidalpert/go-contentstack/v1/management"
 	cschema "github.com/davidalpert/go-contentstack/v1/schema"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"time"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &EnvironmentResource{}
 var _ resource.ResourceWithImportState = &EnvironmentResource{}
+var _ resource.ResourceWithModifyPlan = &EnvironmentResource{}
 
 func NewEnvironmentResource() resource.Resource {
 	return &EnvironmentResource{}
@@ -26,26 +29,42 @@ func NewEnvironmentResource() resource.Resource {
 
 // EnvironmentResource defines the resource implementation.
 type EnvironmentResource struct {
-	client *management.Client
+	clients *clientFactory
 }
 
 // EnvironmentResourceModel describes the resource data model.
 type EnvironmentResourceModel struct {
-	Name          types.String `tfsdk:"name"`
-	URLsByLocale  types.Map    `tfsdk:"urls"`
-	ID            types.String `tfsdk:"id"`
-	UID           types.String `tfsdk:"uid"`
-	CreatedAt     types.String `tfsdk:"created_at"`
-	UpdatedAt     types.String `tfsdk:"updated_at"`
-	Version       types.Int64  `tfsdk:"version"`
-	DeployContent types.Bool   `tfsdk:"deploy_content"`
+	Name          types.String   `tfsdk:"name"`
+	URLsByLocale  types.Map      `tfsdk:"urls"`
+	ID            types.String   `tfsdk:"id"`
+	UID           types.String   `tfsdk:"uid"`
+	CreatedAt     types.String   `tfsdk:"created_at"`
+	CreatedBy     types.String   `tfsdk:"created_by"`
+	UpdatedAt     types.String   `tfsdk:"updated_at"`
+	UpdatedBy     types.String   `tfsdk:"updated_by"`
+	Version       types.Int64    `tfsdk:"version"`
+	DeployContent types.Bool     `tfsdk:"deploy_content"`
+	StackAPIKey   types.String   `tfsdk:"stack_api_key"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (data *EnvironmentResourceModel) Update(g *cschema.Environment) diag.Diagnostics {
 	data.Name = types.StringValue(g.Name)
+	data.UID = types.StringValue(g.UID)
+	data.ID = types.StringValue(g.UID)
 	data.DeployContent = types.BoolValue(g.DeployContent)
 	data.Version = types.Int64Value(int64(g.Version))
-	data.UpdatedAt = types.StringValue("TBD")
+	data.CreatedAt = timestampValue(g.CreatedAt)
+	data.CreatedBy = types.StringValue(g.CreatedBy)
+	data.UpdatedAt = timestampValue(g.UpdatedAt)
+	data.UpdatedBy = types.StringValue(g.UpdatedBy)
+
+	// an environment without urls reads back as an empty list; keep urls
+	// unset rather than planning to remove an empty map on every run
+	if len(g.Urls) == 0 && data.URLsByLocale.IsNull() {
+		return nil
+	}
+
 	uu := make(map[string]attr.Value)
 	for _, u := range g.Urls {
 		uu[u.Locale] = types.StringValue(u.Url)
@@ -56,6 +75,24 @@ func (data *EnvironmentResourceModel) Update(g *cschema.Environment) diag.Diagno
 	return dg
 }
 
+// timestampValue formats a time returned by the API in RFC 3339 format; a
+// time missing from the response is null.
+func timestampValue(t time.Time) types.String {
+	if t.IsZero() {
+		return types.StringNull()
+	}
+	return types.StringValue(t.UTC().Format(time.RFC3339Nano))
+}
+
+// LookupID is what the Environment is looked up by: its uid, or the uid or
+// name it was imported by until it has been read.
+func (data *EnvironmentResourceModel) LookupID() string {
+	if uid := data.UID.ValueString(); uid != "" {
+		return uid
+	}
+	return data.ID.ValueString()
+}
+
 func (data *EnvironmentResourceModel) Export() (*cschema.Environment, diag.Diagnostics) {
 	g := &cschema.Environment{
 		Name:          data.Name.ValueString(),
@@ -77,6 +114,10 @@ func (data *EnvironmentResourceModel) Export() (*cschema.Environment, diag.Diagn
 	return g, dg
 }
 
+// environmentErrorPath maps the properties of an Environment request to the
+// attributes which set them.
+var environmentErrorPath = errorPathToAttributes("deploy_content", "name", "urls")
+
 func (r *EnvironmentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_environment"
 }
@@ -86,9 +127,13 @@ func (r *EnvironmentResource) Schema(ctx context.Context, req resource.SchemaReq
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Environment resource",
 
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
-				MarkdownDescription: "internal terraform resource id (matches the uid when the Environment has been created/imported)",
+				MarkdownDescription: "internal terraform resource id (matches the uid when the Environment has been created/imported); an Environment is imported by its uid or its name",
 				Computed:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
@@ -102,18 +147,31 @@ func (r *EnvironmentResource) Schema(ctx context.Context, req resource.SchemaReq
 				},
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "name of the Environment",
+				MarkdownDescription: "name of the Environment; renaming updates the Environment in place",
 				Required:            true,
 			},
 			"created_at": schema.StringAttribute{
-				MarkdownDescription: "created_at of the Global Field",
+				MarkdownDescription: "time the Environment was created, in RFC 3339 format",
 				Computed:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"created_by": schema.StringAttribute{
+				MarkdownDescription: "uid of the user who created the Environment",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			// updated_at, updated_by and version are unknown in the plan of any
+			// change to the Environment and known once it has been applied
 			"updated_at": schema.StringAttribute{
-				MarkdownDescription: "updated_at of the Global Field",
+				MarkdownDescription: "time the Environment was last changed, in RFC 3339 format",
+				Computed:            true,
+			},
+			"updated_by": schema.StringAttribute{
+				MarkdownDescription: "uid of the user who last changed the Environment",
 				Computed:            true,
 			},
 			"version": schema.Int64Attribute{
@@ -123,12 +181,22 @@ func (r *EnvironmentResource) Schema(ctx context.Context, req resource.SchemaReq
 			"deploy_content": schema.BoolAttribute{
 				MarkdownDescription: "deploy_content",
 				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
 			},
 			"urls": schema.MapAttribute{ // urls by locale
 				ElementType:         types.StringType,
 				Optional:            true,
 				MarkdownDescription: "urls by locale",
 			},
+			"stack_api_key": schema.StringAttribute{
+				MarkdownDescription: stackAPIKeyDescription,
+				Optional:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
@@ -139,18 +207,18 @@ func (r *EnvironmentResource) Configure(ctx context.Context, req resource.Config
 		return
 	}
 
-	client, ok := req.ProviderData.(*management.Client)
+	clients, ok := req.ProviderData.(*clientFactory)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *management.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *clientFactory, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.clients = clients
 }
 
 func (r *EnvironmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -163,29 +231,45 @@ func (r *EnvironmentResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	timeout, dg := data.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	g, dg := data.Export()
 	resp.Diagnostics.Append(dg...)
 
-	wrapper := cschema.SingleEnvironmentWrapper{Environment: *g}
-	created, err := r.client.CreatePublishingEnvironment(&wrapper)
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	created, err := createEnvironment(ctx, client, g)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create Environment %#v, got error: %s", data.Name.ValueString(), err))
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to create Environment %#v", data.Name.ValueString()), err, environmentErrorPath)
 		return
 	}
 
 	// explicitly save the computed fields (but not the optional fields; have to leave those as "unknown")
-	data.Name = types.StringValue(created.Environment.Name)
-	data.UID = types.StringValue(created.Environment.UID)
-	data.ID = types.StringValue(created.Environment.UID)
-	data.CreatedAt = types.StringValue("TBD")
-	data.UpdatedAt = types.StringValue("TBD")
-	data.Version = types.Int64Value(int64(created.Environment.Version))
+	data.Name = types.StringValue(created.Name)
+	data.UID = types.StringValue(created.UID)
+	data.ID = types.StringValue(created.UID)
+	data.CreatedAt = timestampValue(created.CreatedAt)
+	data.CreatedBy = types.StringValue(created.CreatedBy)
+	data.UpdatedAt = timestampValue(created.UpdatedAt)
+	data.UpdatedBy = types.StringValue(created.UpdatedBy)
+	data.Version = types.Int64Value(int64(created.Version))
 
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
 	tflog.Trace(ctx, "created a Environment", map[string]interface{}{
-		"uid":  created.Environment.UID,
-		"name": created.Environment.Name,
+		"uid":  created.UID,
+		"name": created.Name,
 	})
 
 	// Save data into Terraform state
@@ -202,13 +286,37 @@ func (r *EnvironmentResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	g, err := r.client.GetOnePublishingEnvironment(data.Name.ValueString())
+	timeout, dg := data.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the uid keeps track of an environment renamed outside Terraform
+	g, err := findEnvironment(ctx, client, data.LookupID())
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read Environment %#v, got error: %s", data.Name.ValueString(), err))
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to read Environment %#v", data.Name.ValueString()), err, nil)
+		return
+	}
+	if g == nil {
+		// deleted outside Terraform; planning to create it again
+		tflog.Warn(ctx, "Environment not found, removing it from state", map[string]interface{}{
+			"name": data.Name.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
-	data.Update(g)
+	resp.Diagnostics.Append(data.Update(g)...)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -225,23 +333,59 @@ func (r *EnvironmentResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	timeout, dg := data.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	g, dg := data.Export()
 	resp.Diagnostics.Append(dg...)
 	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("uid"), &(g.UID))...)
 
+	// the environment is addressed by its name before any rename
+	var name string
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &name)...)
+
 	tflog.Trace(ctx, "about to update an Environment", map[string]interface{}{
 		"uid":  g.UID,
 		"name": g.Name,
 	})
 
-	_, err := r.client.UpdatePublishingEnvironment(g)
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.clients.strictVersioning {
+		var version types.Int64
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("version"), &version)...)
+
+		current, err := getEnvironment(ctx, client, name)
+		if err != nil {
+			addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to read Environment %#v", data.Name.ValueString()), err, nil)
+			return
+		}
+
+		resp.Diagnostics.Append(checkVersion("Environment", data.Name.ValueString(), version, current.Version)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	updated, err := updateEnvironment(ctx, client, name, g)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update Environment %#v, got error: %s", data.Name.ValueString(), err))
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to update Environment %#v", data.Name.ValueString()), err, environmentErrorPath)
 		return
 	}
 
-	data.UpdatedAt = types.StringValue("TBD")
-	data.Version = types.Int64Value(int64(g.Version))
+	data.UpdatedAt = timestampValue(updated.UpdatedAt)
+	data.UpdatedBy = types.StringValue(updated.UpdatedBy)
+	data.Version = types.Int64Value(int64(updated.Version))
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -257,13 +401,43 @@ func (r *EnvironmentResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	err := r.client.DeletePublishingEnvironment(data.Name.ValueString())
+	timeout, dg := data.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := deleteEnvironment(ctx, client, data.Name.ValueString())
+	if isNotFound(err) {
+		// already deleted outside Terraform
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete Environment %#v, got error: %s", data.Name.ValueString(), err))
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to delete Environment %#v", data.Name.ValueString()), err, nil)
 		return
 	}
 }
 
+func (r *EnvironmentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to check when destroying, nor before the provider is configured
+	if req.Plan.Raw.IsNull() || r.clients == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(r.clients.ValidatePlan(ctx, req.Plan)...)
+}
+
 func (r *EnvironmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	// the id is the uid or the name of the environment; Read replaces a name
+	// with the uid
+	importStatePassthroughStackScopedID(ctx, path.Root("id"), req, resp)
 }
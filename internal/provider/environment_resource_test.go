@@ -0,0 +1,497 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/davidalpert/terraform-provider-contentstack/internal/contentstacktest"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func testAccEnvironmentResourceConfig(api *contentstacktest.Server, body string) string {
+	return testAccProviderConfig(api) + fmt.Sprintf(`
+resource "contentstack_environment" "test" {
+%s
+}
+`, body)
+}
+
+// testAccCheckEnvironmentDestroyed checks that destroying the configuration
+// deleted the environment from the stack.
+func testAccCheckEnvironmentDestroyed(api *contentstacktest.Server, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if _, ok := api.Get(contentstacktest.Environments, name); ok {
+			return fmt.Errorf("expected Environment %#v to be deleted", name)
+		}
+		return nil
+	}
+}
+
+// rfc3339Regexp matches an RFC 3339 time in UTC.
+var rfc3339Regexp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z$`)
+
+// testAccEnvironmentUID returns the uid of an environment in the state.
+func testAccEnvironmentUID(name string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("resource %s not found in state", name)
+		}
+		return rs.Primary.Attributes["uid"], nil
+	}
+}
+
+func TestAccEnvironmentResource(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckEnvironmentDestroyed(api, "staging"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccEnvironmentResourceConfig(api, `
+  name = "staging"
+  urls = {
+    en-us = "https://staging.example.com"
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_environment.test", "name", "staging"),
+					resource.TestCheckResourceAttr("contentstack_environment.test", "urls.%", "1"),
+					resource.TestCheckResourceAttr("contentstack_environment.test", "urls.en-us", "https://staging.example.com"),
+					resource.TestCheckResourceAttr("contentstack_environment.test", "deploy_content", "false"),
+					resource.TestCheckResourceAttr("contentstack_environment.test", "version", "1"),
+					resource.TestMatchResourceAttr("contentstack_environment.test", "created_at", rfc3339Regexp),
+					resource.TestCheckResourceAttr("contentstack_environment.test", "created_by", api.UserUID),
+					resource.TestCheckResourceAttrPair("contentstack_environment.test", "updated_at", "contentstack_environment.test", "created_at"),
+					resource.TestCheckResourceAttr("contentstack_environment.test", "updated_by", api.UserUID),
+					resource.TestCheckResourceAttrSet("contentstack_environment.test", "id"),
+					resource.TestCheckResourceAttrPair("contentstack_environment.test", "id", "contentstack_environment.test", "uid"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// ImportState testing, by name
+			{
+				ResourceName:      "contentstack_environment.test",
+				ImportState:       true,
+				ImportStateId:     "staging",
+				ImportStateVerify: true,
+			},
+			// and by uid
+			{
+				ResourceName:      "contentstack_environment.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccEnvironmentUID("contentstack_environment.test"),
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccEnvironmentResourceConfig(api, `
+  name           = "staging"
+  deploy_content = true
+  urls = {
+    en-us = "https://staging.example.com/en"
+    fr-fr = "https://staging.example.com/fr"
+  }
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_environment.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectUnknownValue("contentstack_environment.test", tfjsonpath.New("updated_at")),
+						plancheck.ExpectUnknownValue("contentstack_environment.test", tfjsonpath.New("version")),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_environment.test", "deploy_content", "true"),
+					resource.TestCheckResourceAttr("contentstack_environment.test", "urls.%", "2"),
+					resource.TestCheckResourceAttr("contentstack_environment.test", "urls.fr-fr", "https://staging.example.com/fr"),
+					resource.TestCheckResourceAttr("contentstack_environment.test", "version", "2"),
+					resource.TestMatchResourceAttr("contentstack_environment.test", "updated_at", rfc3339Regexp),
+					func(s *terraform.State) error {
+						attributes := s.RootModule().Resources["contentstack_environment.test"].Primary.Attributes
+						if attributes["updated_at"] == attributes["created_at"] {
+							return fmt.Errorf("expected updated_at to change from %s", attributes["created_at"])
+						}
+						return nil
+					},
+				),
+			},
+			// Drift testing: a change made outside Terraform shows up in the plan
+			{
+				PreConfig: func() {
+					api.Modify(contentstacktest.Environments, "staging", func(r contentstacktest.Record) {
+						r["deploy_content"] = false
+					})
+				},
+				Config: testAccEnvironmentResourceConfig(api, `
+  name           = "staging"
+  deploy_content = true
+  urls = {
+    en-us = "https://staging.example.com/en"
+    fr-fr = "https://staging.example.com/fr"
+  }
+`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// and is reverted by the next apply
+			{
+				Config: testAccEnvironmentResourceConfig(api, `
+  name           = "staging"
+  deploy_content = true
+  urls = {
+    en-us = "https://staging.example.com/en"
+    fr-fr = "https://staging.example.com/fr"
+  }
+`),
+				Check: func(s *terraform.State) error {
+					r, _ := api.Get(contentstacktest.Environments, "staging")
+					if r["deploy_content"] != true {
+						return fmt.Errorf("expected deploy_content to be reverted to true, got %v", r["deploy_content"])
+					}
+					return nil
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccEnvironmentResourceWithoutURLs(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckEnvironmentDestroyed(api, "preview"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEnvironmentResourceConfig(api, `
+  name = "preview"
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_environment.test", "name", "preview"),
+					resource.TestCheckNoResourceAttr("contentstack_environment.test", "urls"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccEnvironmentResourceDeletedOutsideTerraform(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	config := testAccEnvironmentResourceConfig(api, `
+  name = "staging"
+`)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckEnvironmentDestroyed(api, "staging"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			// an environment deleted in the UI is planned to be created again
+			{
+				PreConfig: func() {
+					api.Remove(contentstacktest.Environments, "staging")
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_environment.test", plancheck.ResourceActionCreate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("contentstack_environment.test", "version", "1"),
+			},
+			// and destroying one which is already gone succeeds
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						testAccRemoveBeforeDestroy(api, contentstacktest.Environments, "staging"),
+					},
+				},
+			},
+		},
+	})
+}
+
+// TestAccEnvironmentResourceNameNeedsEscaping checks that an environment is
+// addressed by its name escaped, as names may hold spaces or a '#'.
+func TestAccEnvironmentResourceNameNeedsEscaping(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckEnvironmentDestroyed(api, "staging site #2"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEnvironmentResourceConfig(api, `
+  name = "staging site #2"
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_environment.test", "name", "staging site #2"),
+					resource.TestCheckResourceAttr("contentstack_environment.test", "version", "1"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			{
+				Config: testAccEnvironmentResourceConfig(api, `
+  name           = "staging site #2"
+  deploy_content = true
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_environment.test", "deploy_content", "true"),
+					resource.TestCheckResourceAttr("contentstack_environment.test", "version", "2"),
+					func(s *terraform.State) error {
+						r, _ := api.Get(contentstacktest.Environments, "staging site #2")
+						if r["deploy_content"] != true {
+							return fmt.Errorf("expected deploy_content to be updated, got %v", r["deploy_content"])
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccEnvironmentResourceRename(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	var uid string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			testAccCheckEnvironmentDestroyed(api, "staging"),
+			testAccCheckEnvironmentDestroyed(api, "preview"),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEnvironmentResourceConfig(api, `
+  name = "staging"
+`),
+				Check: resource.TestCheckResourceAttrWith("contentstack_environment.test", "uid", func(value string) error {
+					uid = value
+					return nil
+				}),
+			},
+			// a rename updates the same environment rather than looking up the new name
+			{
+				Config: testAccEnvironmentResourceConfig(api, `
+  name = "preview"
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_environment.test", plancheck.ResourceActionUpdate),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_environment.test", "name", "preview"),
+					resource.TestCheckResourceAttrWith("contentstack_environment.test", "uid", func(value string) error {
+						if value != uid {
+							return fmt.Errorf("expected the uid to stay %s, got %s", uid, value)
+						}
+						return nil
+					}),
+					func(s *terraform.State) error {
+						if _, ok := api.Get(contentstacktest.Environments, "staging"); ok {
+							return fmt.Errorf("expected the environment to be renamed, found the old name")
+						}
+						return nil
+					},
+				),
+			},
+			// an environment renamed in the UI is found by its uid and renamed back
+			{
+				PreConfig: func() {
+					api.Modify(contentstacktest.Environments, "preview", func(r contentstacktest.Record) {
+						r["name"] = "qa"
+					})
+				},
+				Config: testAccEnvironmentResourceConfig(api, `
+  name = "preview"
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_environment.test", plancheck.ResourceActionUpdate),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: func(s *terraform.State) error {
+					r, ok := api.Get(contentstacktest.Environments, "preview")
+					if !ok || r["uid"] != uid {
+						return fmt.Errorf("expected environment %s to be renamed back, got %v", uid, r)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccEnvironmentResourceStrictVersioning(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	config := func(deployContent bool) string {
+		return testAccProviderConfig(api, "  strict_versioning = true") + fmt.Sprintf(`
+resource "contentstack_environment" "test" {
+  name           = "staging"
+  deploy_content = %t
+}
+`, deployContent)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckEnvironmentDestroyed(api, "staging"),
+		Steps: []resource.TestStep{
+			{
+				Config: config(false),
+			},
+			// an editor changes the environment between plan and apply
+			{
+				Config: config(true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						testAccPlanCheckFunc(func() error {
+							api.Modify(contentstacktest.Environments, "staging", func(r contentstacktest.Record) {
+								r["urls"] = []interface{}{map[string]interface{}{"locale": "en-us", "url": "https://editor.example.com"}}
+							})
+							return nil
+						}),
+					},
+				},
+				ExpectError: regexp.MustCompile(`(?s)Conflicting Change.*state has version 1 and the stack has version 2`),
+			},
+			// once refreshed, the change can be reviewed and overwritten
+			{
+				Config: config(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_environment.test", "deploy_content", "true"),
+					resource.TestCheckResourceAttr("contentstack_environment.test", "version", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestTimestampValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		time time.Time
+		want types.String
+	}{
+		{"utc", time.Date(2023, 6, 1, 12, 30, 0, 0, time.UTC), types.StringValue("2023-06-01T12:30:00Z")},
+		{"milliseconds", time.Date(2023, 6, 1, 12, 30, 0, 125e6, time.UTC), types.StringValue("2023-06-01T12:30:00.125Z")},
+		{"other zone", time.Date(2023, 6, 1, 14, 30, 0, 0, time.FixedZone("CEST", 2*60*60)), types.StringValue("2023-06-01T12:30:00Z")},
+		{"missing", time.Time{}, types.StringNull()},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := timestampValue(tt.time); !got.Equal(tt.want) {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccEnvironmentResourceOtherStackWithManagementToken(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// a management token cannot reach another stack, which fails the plan
+			{
+				Config: testAccEnvironmentResourceConfig(api, `
+  name          = "staging"
+  stack_api_key = "blt0000000000000other"
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Unsupported ContentStack Stack API Key`),
+			},
+		},
+	})
+}
+
+// TestAccEnvironmentResourceCassette replays the lifecycle of an environment
+// recorded with testAccCassetteProviderFactories; it needs no credentials
+// unless CONTENTSTACK_CASSETTE_MODE=record. The committed cassette was
+// recorded from the fake API; recording it from a real stack replaces it.
+func TestAccEnvironmentResourceCassette(t *testing.T) {
+	config := func(body string) string {
+		return testAccCassetteProviderConfig() + fmt.Sprintf(`
+resource "contentstack_environment" "test" {
+%s
+}
+`, body)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccCassetteProviderFactories(t, testAccCassetteSecrets()...),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`
+  name = "terraform-provider-contentstack-test"
+  urls = {
+    en-us = "https://staging.example.com"
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_environment.test", "name", "terraform-provider-contentstack-test"),
+					resource.TestCheckResourceAttr("contentstack_environment.test", "version", "1"),
+					resource.TestCheckResourceAttrPair("contentstack_environment.test", "id", "contentstack_environment.test", "uid"),
+				),
+			},
+			{
+				Config: config(`
+  name           = "terraform-provider-contentstack-test"
+  deploy_content = true
+  urls = {
+    en-us = "https://staging.example.com"
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_environment.test", "deploy_content", "true"),
+					resource.TestCheckResourceAttr("contentstack_environment.test", "version", "2"),
+				),
+			},
+		},
+	})
+}
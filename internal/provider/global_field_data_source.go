@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"fmt"
-	"github.com/davidalpert/go-contentstack/v1/management"
 	cschema "github.com/davidalpert/go-contentstack/v1/schema"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -20,7 +19,7 @@ func NewGlobalFieldDataSource() datasource.DataSource {
 
 // GlobalFieldDataSource defines the data source implementation.
 type GlobalFieldDataSource struct {
-	client *management.Client
+	clients *clientFactory
 }
 
 // GlobalFieldDataSourceModel describes the data source data model.
@@ -30,6 +29,7 @@ type GlobalFieldDataSourceModel struct {
 	Fields      []SchemaFieldDataSourceModel `tfsdk:"field"`
 	ID          types.String                 `tfsdk:"id"`
 	Title       types.String                 `tfsdk:"title"`
+	StackAPIKey types.String                 `tfsdk:"stack_api_key"`
 	UID         types.String                 `tfsdk:"uid"`
 	UpdatedAt   types.String                 `tfsdk:"updated_at"`
 }
@@ -115,6 +115,11 @@ func (d *GlobalFieldDataSource) Schema(ctx context.Context, req datasource.Schem
 				Computed:            true,
 			},
 			"field": BuildComputedFieldsSchema(),
+			"stack_api_key": schema.StringAttribute{
+				MarkdownDescription: stackAPIKeyDescription,
+				Optional:            true,
+				Sensitive:           true,
+			},
 		},
 	}
 }
@@ -125,18 +130,18 @@ func (d *GlobalFieldDataSource) Configure(ctx context.Context, req datasource.Co
 		return
 	}
 
-	client, ok := req.ProviderData.(*management.Client)
+	clients, ok := req.ProviderData.(*clientFactory)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *management.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *clientFactory, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client
+	d.clients = clients
 }
 
 func (d *GlobalFieldDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -149,11 +154,15 @@ func (d *GlobalFieldDataSource) Read(ctx context.Context, req datasource.ReadReq
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	g, err := d.client.GetOneGlobalField(data.UID.ValueString())
+	client, dg := d.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	g, err := getGlobalField(ctx, client, data.UID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read GlobalField %#v, got error: %s", data.UID.ValueString(), err))
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to read GlobalField %#v", data.UID.ValueString()), err, nil)
 		return
 	}
 
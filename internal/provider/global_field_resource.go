@@ -2,27 +2,42 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"github.com/davidalpert/go-contentstack/v1/management"
 	cschema "github.com/davidalpert/go-contentstack/v1/schema"
 	myboolplanmodifiers "github.com/davidalpert/terraform-provider-contentstack/internal/tfutils/boolplanmodifier"
+	"github.com/davidalpert/terraform-provider-contentstack/internal/tfutils/jsonvalidator"
+	"github.com/davidalpert/terraform-provider-contentstack/internal/tfutils/schemajson"
+	"github.com/davidalpert/terraform-provider-contentstack/internal/tfutils/siblingvalidator"
 	mystringplanmodifiers "github.com/davidalpert/terraform-provider-contentstack/internal/tfutils/stringplanmodifiers"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"reflect"
+	"regexp"
+	"strings"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &GlobalFieldResource{}
 var _ resource.ResourceWithImportState = &GlobalFieldResource{}
+var _ resource.ResourceWithUpgradeState = &GlobalFieldResource{}
+var _ resource.ResourceWithConfigValidators = &GlobalFieldResource{}
+var _ resource.ResourceWithModifyPlan = &GlobalFieldResource{}
+var _ resource.ResourceWithValidateConfig = &GlobalFieldResource{}
 
 func NewGlobalFieldResource() resource.Resource {
 	return &GlobalFieldResource{}
@@ -30,66 +45,185 @@ func NewGlobalFieldResource() resource.Resource {
 
 // GlobalFieldResource defines the resource implementation.
 type GlobalFieldResource struct {
-	client *management.Client
+	clients *clientFactory
 }
 
 // GlobalFieldResourceModel describes the resource data model.
 type GlobalFieldResourceModel struct {
-	Description types.String                          `tfsdk:"description"`
-	Fields      []GlobalFieldSchemaFieldResourceModel `tfsdk:"fields"`
-	ID          types.String                          `tfsdk:"id"`
-	Title       types.String                          `tfsdk:"title"`
-	UID         types.String                          `tfsdk:"uid"`
+	AllowDestructiveChanges types.Bool                            `tfsdk:"allow_destructive_changes"`
+	DeletionProtection      types.Bool                            `tfsdk:"deletion_protection"`
+	Description             types.String                          `tfsdk:"description"`
+	Fields                  []GlobalFieldSchemaFieldResourceModel `tfsdk:"fields"`
+	ForceDelete             types.Bool                            `tfsdk:"force_delete"`
+	ID                      types.String                          `tfsdk:"id"`
+	SchemaJSON              schemajson.Value                      `tfsdk:"schema_json"`
+	StackAPIKey             types.String                          `tfsdk:"stack_api_key"`
+	Title                   types.String                          `tfsdk:"title"`
+	UID                     types.String                          `tfsdk:"uid"`
+	Version                 types.Int64                           `tfsdk:"version"`
+	Timeouts                timeouts.Value                        `tfsdk:"timeouts"`
 }
 
-func (data *GlobalFieldResourceModel) Update(g *cschema.GlobalField) {
+func (data *GlobalFieldResourceModel) Update(g *globalFieldJSON) diag.Diagnostics {
+	var diags diag.Diagnostics
+
 	tflog.Warn(context.Background(), "Update Description", map[string]interface{}{
 		"api":   g.Description,
 		"model": data.Description.String(),
 	})
 	data.Description = types.StringValue(g.Description)
-	data.Fields = make([]GlobalFieldSchemaFieldResourceModel, len(g.Schema))
 	data.ID = types.StringValue(g.UID)
 	data.Title = types.StringValue(g.Title)
 	data.UID = types.StringValue(g.UID)
+	data.Version = types.Int64Value(int64(g.Version))
+
+	if data.UsesSchemaJSON() {
+		data.SchemaJSON = schemajson.NewValue(string(g.Schema))
+		return diags
+	}
 
-	for i, f := range g.Schema {
-		data.Fields[i] = GlobalFieldSchemaFieldResourceModel{}
+	var fields []schemaField
+	if err := json.Unmarshal(g.Schema, &fields); err != nil {
+		diags.AddError("Unable to parse GlobalField schema", err.Error())
+		return diags
+	}
+
+	// keep the prior field values around so that values which are only
+	// semantically equal to what the server returns are not replaced
+	prior := make(map[string]GlobalFieldSchemaFieldResourceModel, len(data.Fields))
+	for _, f := range data.Fields {
+		prior[f.Uid.ValueString()] = f
+	}
+
+	data.Fields = make([]GlobalFieldSchemaFieldResourceModel, len(fields))
+	for i, f := range fields {
+		data.Fields[i] = prior[f.Uid]
 		data.Fields[i].Update(f)
 	}
+
+	return diags
 }
 
-func (data *GlobalFieldResourceModel) Export() *cschema.GlobalField {
-	g := &cschema.GlobalField{
+func (data *GlobalFieldResourceModel) Export() (*globalFieldJSON, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	g := &globalFieldJSON{
 		Description: data.Description.ValueString(),
-		Schema:      make([]cschema.Field, len(data.Fields)),
 		Title:       data.Title.ValueString(),
 		UID:         data.UID.ValueString(),
 	}
 
+	if data.UsesSchemaJSON() {
+		g.Schema = json.RawMessage(data.SchemaJSON.ValueString())
+		return g, diags
+	}
+
+	fields := make([]schemaField, len(data.Fields))
 	for i, fieldData := range data.Fields {
-		g.Schema[i] = fieldData.Export()
+		fields[i] = fieldData.Export()
+	}
+
+	s, err := json.Marshal(fields)
+	if err != nil {
+		diags.AddError("Unable to serialize GlobalField schema", err.Error())
+		return g, diags
+	}
+	g.Schema = s
+
+	return g, diags
+}
+
+// UsesSchemaJSON reports whether the field schema is managed as raw JSON
+// through schema_json rather than through fields.
+func (data *GlobalFieldResourceModel) UsesSchemaJSON() bool {
+	return !data.SchemaJSON.IsNull()
+}
+
+// fieldErrorAttributes maps the properties of a field in the schema of a
+// request to the attributes of fields which set them.
+var fieldErrorAttributes = map[string]string{
+	"data_type":                  "data_type",
+	"display_name":               "display_name",
+	"endDate":                    "end_date",
+	"field_metadata.description": "description",
+	"field_metadata.instruction": "instruction",
+	"field_metadata.placeholder": "placeholder",
+	"format":                     "format",
+	"inbuilt_model":              "inbuilt_model",
+	"indexed":                    "indexed",
+	"mandatory":                  "mandatory",
+	"multiple":                   "multiple",
+	"non_localizable":            "non_localizable",
+	"startDate":                  "start_date",
+	"uid":                        "uid",
+	"unique":                     "unique",
+}
+
+// errorPath maps a property of a GlobalField request, such as "schema.3.uid",
+// to the attribute which sets it.
+func (data *GlobalFieldResourceModel) errorPath(key string) (path.Path, bool) {
+	first, index, rest := splitErrorKey(key)
+
+	switch first {
+	case "description", "title", "uid":
+		return path.Root(first), true
+	case "schema":
+		if data.UsesSchemaJSON() {
+			return path.Root("schema_json"), true
+		}
+		if index < 0 || index >= len(data.Fields) {
+			return path.Root("fields"), true
+		}
+		p := path.Root("fields").AtListIndex(index)
+		if name, ok := fieldErrorAttributes[rest]; ok {
+			return p.AtName(name), true
+		}
+		return p, true
 	}
 
-	return g
+	return path.Empty(), false
+}
+
+// schemaField extends cschema.Field with the properties which go-contentstack
+// does not model yet.
+type schemaField struct {
+	cschema.Field
+	StartDate *string `json:"startDate,omitempty"`
+	EndDate   *string `json:"endDate,omitempty"`
+}
+
+// dateDefaultValue is the shape of the default_value of an isodate field.
+type dateDefaultValue struct {
+	Custom bool   `json:"custom"`
+	Date   string `json:"date"`
+	Time   string `json:"time"`
 }
 
 type GlobalFieldSchemaFieldResourceModel struct {
-	DataType    types.String `tfsdk:"data_type"`
-	Description types.String `tfsdk:"description"`
-	DisplayName types.String `tfsdk:"display_name"`
-	DefaultText types.String `tfsdk:"default_text"`
-	DefaultBool types.Bool   `tfsdk:"default_bool"`
-	Format      types.String `tfsdk:"format"`
-	Mandatory   types.Bool   `tfsdk:"mandatory"`
-	Multiple    types.Bool   `tfsdk:"multiple"`
-	Placeholder types.String `tfsdk:"placeholder"`
-	Instruction types.String `tfsdk:"instruction"`
-	Uid         types.String `tfsdk:"uid"`
-	Unique      types.Bool   `tfsdk:"unique"`
-}
-
-func (data *GlobalFieldSchemaFieldResourceModel) Update(f cschema.Field) {
+	DataType       types.String  `tfsdk:"data_type"`
+	DateRange      types.Bool    `tfsdk:"date_range"`
+	Description    types.String  `tfsdk:"description"`
+	DisplayName    types.String  `tfsdk:"display_name"`
+	DefaultText    types.String  `tfsdk:"default_text"`
+	DefaultBool    types.Bool    `tfsdk:"default_bool"`
+	DefaultDate    types.String  `tfsdk:"default_date"`
+	DefaultJSON    types.String  `tfsdk:"default_json"`
+	DefaultNumber  types.Float64 `tfsdk:"default_number"`
+	EndDate        types.String  `tfsdk:"end_date"`
+	Format         types.String  `tfsdk:"format"`
+	InbuiltModel   types.Bool    `tfsdk:"inbuilt_model"`
+	Indexed        types.Bool    `tfsdk:"indexed"`
+	Mandatory      types.Bool    `tfsdk:"mandatory"`
+	Multiple       types.Bool    `tfsdk:"multiple"`
+	NonLocalizable types.Bool    `tfsdk:"non_localizable"`
+	Placeholder    types.String  `tfsdk:"placeholder"`
+	Instruction    types.String  `tfsdk:"instruction"`
+	StartDate      types.String  `tfsdk:"start_date"`
+	Uid            types.String  `tfsdk:"uid"`
+	Unique         types.Bool    `tfsdk:"unique"`
+}
+
+func (data *GlobalFieldSchemaFieldResourceModel) Update(f schemaField) {
 	data.DataType = types.StringValue(f.DataType)
 	data.Description = types.StringValue(f.FieldMetadata.Description)
 	data.DisplayName = types.StringValue(f.DisplayName)
@@ -98,41 +232,83 @@ func (data *GlobalFieldSchemaFieldResourceModel) Update(f cschema.Field) {
 	} else {
 		data.Format = types.StringNull()
 	}
+	// the API leaves out empty and false properties, which read back as the
+	// defaults of their attributes rather than as null
+	data.Placeholder = types.StringValue("")
 	if f.FieldMetadata.Placeholder != nil {
 		data.Placeholder = types.StringValue(*f.FieldMetadata.Placeholder)
-	} else {
-		data.Placeholder = types.StringNull()
 	}
+	data.Instruction = types.StringValue("")
 	if f.FieldMetadata.Instruction != nil {
 		data.Instruction = types.StringValue(*f.FieldMetadata.Instruction)
-	} else {
-		data.Instruction = types.StringNull()
-	}
-	if f.FieldMetadata.DefaultValue != nil {
-		switch v := f.FieldMetadata.DefaultValue.(type) {
-		case string:
-			data.DefaultText = types.StringValue(v)
-		case bool:
-			data.DefaultBool = types.BoolValue(v)
-		default:
-			// TODO: show warning: unsupported
-		}
 	}
+	data.updateDefaultValue(f.DataType, f.FieldMetadata.DefaultValue)
+	data.DateRange = types.BoolValue(f.StartDate != nil || f.EndDate != nil)
+	data.StartDate = types.StringPointerValue(f.StartDate)
+	data.EndDate = types.StringPointerValue(f.EndDate)
+	data.InbuiltModel = types.BoolValue(f.InbuiltModel != nil && *f.InbuiltModel)
+	data.Indexed = types.BoolValue(f.Indexed != nil && *f.Indexed)
 	data.Mandatory = types.BoolValue(f.Mandatory)
 	data.Multiple = types.BoolValue(f.Multiple)
+	data.NonLocalizable = types.BoolValue(f.NonLocalizable != nil && *f.NonLocalizable)
 	data.Uid = types.StringValue(f.Uid)
-	if f.Unique != nil {
-		data.Unique = types.BoolValue(*f.Unique)
-	} else {
-		data.Unique = types.BoolNull()
+	data.Unique = types.BoolValue(f.Unique != nil && *f.Unique)
+}
+
+// updateDefaultValue sets the one default_* attribute which matches the shape
+// of the default value returned by the server and clears the others.
+func (data *GlobalFieldSchemaFieldResourceModel) updateDefaultValue(dataType string, defaultValue interface{}) {
+	priorJSON := data.DefaultJSON
+
+	data.DefaultBool = types.BoolNull()
+	data.DefaultDate = types.StringNull()
+	data.DefaultJSON = types.StringNull()
+	data.DefaultNumber = types.Float64Null()
+	data.DefaultText = types.StringNull()
+
+	if defaultValue == nil {
+		return
+	}
+
+	switch dataType {
+	case "isodate":
+		var d dateDefaultValue
+		if b, err := json.Marshal(defaultValue); err == nil && json.Unmarshal(b, &d) == nil && d.Custom && d.Date != "" {
+			data.DefaultDate = types.StringValue(d.Date)
+		}
+		return
+	case "json":
+		b, err := json.Marshal(defaultValue)
+		if err != nil {
+			return
+		}
+		if jsonEqual(priorJSON.ValueString(), string(b)) {
+			data.DefaultJSON = priorJSON
+		} else {
+			data.DefaultJSON = types.StringValue(string(b))
+		}
+		return
+	}
+
+	switch v := defaultValue.(type) {
+	case string:
+		data.DefaultText = types.StringValue(v)
+	case bool:
+		data.DefaultBool = types.BoolValue(v)
+	case float64:
+		data.DefaultNumber = types.Float64Value(v)
+	default:
+		// TODO: show warning: unsupported
 	}
 }
 
-func (data *GlobalFieldSchemaFieldResourceModel) Export() cschema.Field {
-	field := cschema.Field{
-		DataType:      data.DataType.ValueString(),
-		Uid:           data.Uid.ValueString(),
-		FieldMetadata: cschema.FieldMetadata{},
+func (data *GlobalFieldSchemaFieldResourceModel) Export() schemaField {
+	field := schemaField{
+		Field: cschema.Field{
+			DataType:      data.DataType.ValueString(),
+			Uid:           data.Uid.ValueString(),
+			FieldMetadata: cschema.FieldMetadata{},
+		},
 	}
 
 	if !data.DisplayName.IsNull() {
@@ -141,10 +317,24 @@ func (data *GlobalFieldSchemaFieldResourceModel) Export() cschema.Field {
 		field.DisplayName = field.Uid
 	}
 
-	if !data.DefaultBool.IsNull() {
+	// default_bool and default_text are computed, so they are unknown rather
+	// than null until the server has answered
+	if !data.DefaultBool.IsNull() && !data.DefaultBool.IsUnknown() {
 		field.FieldMetadata.DefaultValue = data.DefaultBool.ValueBool()
-	} else if !data.DefaultText.IsNull() {
+	} else if !data.DefaultText.IsNull() && !data.DefaultText.IsUnknown() {
 		field.FieldMetadata.DefaultValue = data.DefaultText.ValueString()
+	} else if !data.DefaultNumber.IsNull() {
+		field.FieldMetadata.DefaultValue = data.DefaultNumber.ValueFloat64()
+	} else if !data.DefaultDate.IsNull() {
+		field.FieldMetadata.DefaultValue = dateDefaultValue{Custom: true, Date: data.DefaultDate.ValueString()}
+	} else if !data.DefaultJSON.IsNull() {
+		// validated as well-formed JSON at plan time
+		field.FieldMetadata.DefaultValue = json.RawMessage(data.DefaultJSON.ValueString())
+	}
+
+	if data.DateRange.ValueBool() {
+		field.StartDate = data.StartDate.ValueStringPointer()
+		field.EndDate = data.EndDate.ValueStringPointer()
 	}
 
 	if !data.Description.IsNull() {
@@ -159,6 +349,14 @@ func (data *GlobalFieldSchemaFieldResourceModel) Export() cschema.Field {
 		field.FieldMetadata.Instruction = cschema.StrPtr(data.Instruction.ValueString())
 	}
 
+	if !data.InbuiltModel.IsNull() {
+		field.InbuiltModel = cschema.BoolPtr(data.InbuiltModel.ValueBool())
+	}
+
+	if !data.Indexed.IsNull() {
+		field.Indexed = cschema.BoolPtr(data.Indexed.ValueBool())
+	}
+
 	if !data.Mandatory.IsNull() {
 		field.Mandatory = data.Mandatory.ValueBool()
 	}
@@ -167,6 +365,10 @@ func (data *GlobalFieldSchemaFieldResourceModel) Export() cschema.Field {
 		field.Multiple = data.Multiple.ValueBool()
 	}
 
+	if !data.NonLocalizable.IsNull() {
+		field.NonLocalizable = cschema.BoolPtr(data.NonLocalizable.ValueBool())
+	}
+
 	if !data.Unique.IsNull() {
 		field.Unique = cschema.BoolPtr(data.Unique.ValueBool())
 	}
@@ -174,6 +376,15 @@ func (data *GlobalFieldSchemaFieldResourceModel) Export() cschema.Field {
 	return field
 }
 
+// jsonEqual reports whether two JSON documents decode to the same value.
+func jsonEqual(a, b string) bool {
+	var av, bv interface{}
+	if json.Unmarshal([]byte(a), &av) != nil || json.Unmarshal([]byte(b), &bv) != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
 func (r *GlobalFieldResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_global_field"
 }
@@ -183,7 +394,26 @@ func (r *GlobalFieldResource) Schema(ctx context.Context, req resource.SchemaReq
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "GlobalField resource",
 
+		// version 1 changed fields from a set to a list so that field order is preserved
+		Version: 1,
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+
 		Attributes: map[string]schema.Attribute{
+			"allow_destructive_changes": schema.BoolAttribute{
+				MarkdownDescription: "apply changes to fields which lose or invalidate the data of existing entries, i.e. removing a field, changing its `data_type`, turning on `unique` or `mandatory` or turning off `multiple`, with a warning rather than failing the plan. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "refuse to delete the GlobalField while content types use it, as deleting it also deletes its data from their entries; the error lists the content types. Defaults to false. Like `force_delete`, it only takes effect once applied, so set it in an apply before the one which destroys the GlobalField.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
 			"description": schema.StringAttribute{
 				MarkdownDescription: "description of the GlobalField",
 				Optional:            true,
@@ -193,6 +423,12 @@ func (r *GlobalFieldResource) Schema(ctx context.Context, req resource.SchemaReq
 				},
 			},
 			"fields": BuildFieldsSchema(),
+			"force_delete": schema.BoolAttribute{
+				MarkdownDescription: "delete the GlobalField even though content types use it, overriding `deletion_protection`; the GlobalField and its data are removed from their schemas and entries. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "GlobalField identifier",
@@ -200,6 +436,19 @@ func (r *GlobalFieldResource) Schema(ctx context.Context, req resource.SchemaReq
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"schema_json": schema.StringAttribute{
+				CustomType:          schemajson.Type{},
+				MarkdownDescription: "field schema of the GlobalField as a Contentstack schema JSON array, used verbatim; an alternative to `fields` for field types which `fields` does not cover. Key order and properties which the server fills in with their defaults are ignored when comparing.",
+				Optional:            true,
+			},
+			"stack_api_key": schema.StringAttribute{
+				MarkdownDescription: stackAPIKeyDescription,
+				Optional:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"title": schema.StringAttribute{
 				MarkdownDescription: "title of the GlobalField",
 				Optional:            true,
@@ -212,12 +461,18 @@ func (r *GlobalFieldResource) Schema(ctx context.Context, req resource.SchemaReq
 				MarkdownDescription: "uid of the GlobalField",
 				Required:            true,
 			},
+			"version": schema.Int64Attribute{
+				MarkdownDescription: "version number of the GlobalField",
+				Computed:            true,
+			},
 		},
 	}
 }
 
-func BuildFieldsSchema() schema.SetNestedAttribute {
-	return schema.SetNestedAttribute{
+// BuildFieldsSchema builds the schema for an ordered list of fields; Contentstack
+// renders fields in the order they appear in the schema so order is significant.
+func BuildFieldsSchema() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
 		NestedObject: schema.NestedAttributeObject{
 			Attributes: map[string]schema.Attribute{
 				"data_type": schema.StringAttribute{
@@ -241,26 +496,64 @@ func BuildFieldsSchema() schema.SetNestedAttribute {
 					Computed:            true,
 					Default:             stringdefault.StaticString(""),
 				},
+				"date_range": schema.BoolAttribute{
+					MarkdownDescription: "restrict an isodate field to dates between start_date and end_date; requires at least one of them",
+					Optional:            true,
+					Computed:            true,
+					PlanModifiers: []planmodifier.Bool{
+						myboolplanmodifiers.DefaultValue(false),
+					},
+					Validators: []validator.Bool{
+						siblingvalidator.RequiresValueOneOf("data_type", "isodate"),
+						// the API has no flag of its own: a range is read back
+						// from the dates
+						siblingvalidator.TrueRequiresOneOf("start_date", "end_date"),
+					},
+				},
 				"default_bool": schema.BoolAttribute{
-					MarkdownDescription: "default boolean value for the field",
+					MarkdownDescription: "default boolean value for a boolean field",
 					Optional:            true,
 					Computed:            true,
 					//Default: booldefault.StaticBool(false),
 					Validators: []validator.Bool{
-						boolvalidator.ConflictsWith(
-							path.MatchRelative().AtParent().AtName("default_text"),
-						),
+						boolvalidator.ConflictsWith(defaultValueConflicts("default_bool")...),
+						siblingvalidator.RequiresValueOneOf("data_type", "boolean"),
+					},
+				},
+				"default_date": schema.StringAttribute{
+					MarkdownDescription: "default date (YYYY-MM-DD) for an isodate field",
+					Optional:            true,
+					Validators: []validator.String{
+						stringvalidator.ConflictsWith(defaultValueConflicts("default_date")...),
+						stringvalidator.RegexMatches(isoDateRegex, "must be a date in YYYY-MM-DD format"),
+						siblingvalidator.RequiresValueOneOf("data_type", "isodate"),
+					},
+				},
+				"default_json": schema.StringAttribute{
+					MarkdownDescription: "default value for a json field, as a JSON document",
+					Optional:            true,
+					Validators: []validator.String{
+						stringvalidator.ConflictsWith(defaultValueConflicts("default_json")...),
+						jsonvalidator.Valid(),
+						siblingvalidator.RequiresValueOneOf("data_type", "json"),
+					},
+				},
+				"default_number": schema.Float64Attribute{
+					MarkdownDescription: "default numeric value for a number field",
+					Optional:            true,
+					Validators: []validator.Float64{
+						float64validator.ConflictsWith(defaultValueConflicts("default_number")...),
+						siblingvalidator.RequiresValueOneOf("data_type", "number"),
 					},
 				},
 				"default_text": schema.StringAttribute{
-					MarkdownDescription: "default boolean value for the field",
+					MarkdownDescription: "default text value for a text field",
 					Optional:            true,
 					Computed:            true,
 					//Default:             stringdefault.StaticString(""),
 					Validators: []validator.String{
-						stringvalidator.ConflictsWith(
-							path.MatchRelative().AtParent().AtName("default_bool"),
-						),
+						stringvalidator.ConflictsWith(defaultValueConflicts("default_text")...),
+						siblingvalidator.RequiresValueOneOf("data_type", "text"),
 					},
 				},
 				"display_name": schema.StringAttribute{
@@ -271,9 +564,21 @@ func BuildFieldsSchema() schema.SetNestedAttribute {
 						mystringplanmodifiers.DefaultValueCopiedFromAnotherField("uid"),
 					},
 				},
+				"end_date": schema.StringAttribute{
+					MarkdownDescription: "last date (YYYY-MM-DD) accepted by an isodate field when date_range is set",
+					Optional:            true,
+					Validators: []validator.String{
+						stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("date_range")),
+						stringvalidator.RegexMatches(isoDateRegex, "must be a date in YYYY-MM-DD format"),
+						siblingvalidator.RequiresValueOneOf("data_type", "isodate"),
+					},
+				},
 				"format": schema.StringAttribute{
-					MarkdownDescription: "format of the field",
+					MarkdownDescription: "format (a regular expression) which values of a text field must match",
 					Optional:            true,
+					Validators: []validator.String{
+						siblingvalidator.RequiresValueOneOf("data_type", "text"),
+					},
 				},
 				"placeholder": schema.StringAttribute{
 					MarkdownDescription: "placeholder text for the field",
@@ -291,14 +596,39 @@ func BuildFieldsSchema() schema.SetNestedAttribute {
 						mystringplanmodifiers.DefaultValue(""),
 					},
 				},
+				"start_date": schema.StringAttribute{
+					MarkdownDescription: "first date (YYYY-MM-DD) accepted by an isodate field when date_range is set",
+					Optional:            true,
+					Validators: []validator.String{
+						stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("date_range")),
+						stringvalidator.RegexMatches(isoDateRegex, "must be a date in YYYY-MM-DD format"),
+						siblingvalidator.RequiresValueOneOf("data_type", "isodate"),
+					},
+				},
 				//"display_type": schema.StringAttribute{
 				//	MarkdownDescription: "display type of the field",
 				//	Optional:            true,
 				//},
 				"uid": schema.StringAttribute{
-					MarkdownDescription: "uid of the field",
+					MarkdownDescription: "uid of the field; lowercase letters, digits and underscores, starting with a letter, unique within the Global Field and not one of the uids reserved by Contentstack",
 					Required:            true,
 				},
+				"inbuilt_model": schema.BoolAttribute{
+					MarkdownDescription: "is this field part of a built-in model",
+					Optional:            true,
+					Computed:            true,
+					PlanModifiers: []planmodifier.Bool{
+						myboolplanmodifiers.DefaultValue(false),
+					},
+				},
+				"indexed": schema.BoolAttribute{
+					MarkdownDescription: "is this field indexed for search",
+					Optional:            true,
+					Computed:            true,
+					PlanModifiers: []planmodifier.Bool{
+						myboolplanmodifiers.DefaultValue(false),
+					},
+				},
 				"mandatory": schema.BoolAttribute{
 					MarkdownDescription: "is this field mandatory",
 					Optional:            true,
@@ -315,8 +645,16 @@ func BuildFieldsSchema() schema.SetNestedAttribute {
 						myboolplanmodifiers.DefaultValue(false),
 					},
 				},
+				"non_localizable": schema.BoolAttribute{
+					MarkdownDescription: "does this field keep the same value across all locales; only meaningful on stacks with more than one locale",
+					Optional:            true,
+					Computed:            true,
+					PlanModifiers: []planmodifier.Bool{
+						myboolplanmodifiers.DefaultValue(false),
+					},
+				},
 				"unique": schema.BoolAttribute{
-					MarkdownDescription: "must this field be unique",
+					MarkdownDescription: "must the values of this field be unique across entries; cannot be combined with `multiple`, as Contentstack does not enforce uniqueness on multiple fields. Any number of fields may be unique.",
 					Optional:            true,
 					Computed:            true,
 					PlanModifiers: []planmodifier.Bool{
@@ -325,29 +663,441 @@ func BuildFieldsSchema() schema.SetNestedAttribute {
 				},
 			},
 		},
-		Required:            true,
-		MarkdownDescription: "field schema of the Global Field",
+		Optional:            true,
+		MarkdownDescription: "ordered field schema of the Global Field",
 	}
 }
 
+var isoDateRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// defaultValueAttributes are the mutually exclusive default_* attributes of a field.
+var defaultValueAttributes = []string{"default_bool", "default_date", "default_json", "default_number", "default_text"}
+
+// defaultValueConflicts returns the paths of every default_* attribute except name.
+func defaultValueConflicts(name string) []path.Expression {
+	var conflicts []path.Expression
+	for _, a := range defaultValueAttributes {
+		if a != name {
+			conflicts = append(conflicts, path.MatchRelative().AtParent().AtName(a))
+		}
+	}
+	return conflicts
+}
+
+// fieldUIDRegex matches the field uids which Contentstack accepts.
+var fieldUIDRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// reservedFieldUIDs are the field uids which Contentstack reserves for system use.
+var reservedFieldUIDs = map[string]bool{
+	"_id":             true,
+	"_owner":          true,
+	"_version":        true,
+	"acl":             true,
+	"api_key":         true,
+	"created_at":      true,
+	"created_by":      true,
+	"deleted_at":      true,
+	"id":              true,
+	"inbuilt_class":   true,
+	"locale":          true,
+	"publish_details": true,
+	"tags":            true,
+	"title":           true,
+	"uid":             true,
+	"updated_at":      true,
+	"updated_by":      true,
+	"url":             true,
+}
+
+// ValidateConfig checks the rules which span more than one field, so that
+// they fail at plan time rather than part way through an apply.
+func (r *GlobalFieldResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var fieldsList types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("fields"), &fieldsList)...)
+
+	if resp.Diagnostics.HasError() || fieldsList.IsNull() || fieldsList.IsUnknown() {
+		return
+	}
+
+	var fields []GlobalFieldSchemaFieldResourceModel
+
+	resp.Diagnostics.Append(fieldsList.ElementsAs(ctx, &fields, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateFields(fields)...)
+}
+
+func validateFields(fields []GlobalFieldSchemaFieldResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	seen := make(map[string]int, len(fields))
+	for i, f := range fields {
+		uidPath := path.Root("fields").AtListIndex(i).AtName("uid")
+
+		if !f.Uid.IsUnknown() {
+			uid := f.Uid.ValueString()
+
+			if !fieldUIDRegex.MatchString(uid) {
+				diags.AddAttributeError(uidPath, "Invalid Field UID",
+					fmt.Sprintf("field uid %#v must contain only lowercase letters, digits and underscores and start with a letter", uid))
+			} else if reservedFieldUIDs[uid] {
+				diags.AddAttributeError(uidPath, "Reserved Field UID",
+					fmt.Sprintf("field uid %#v is reserved by Contentstack; choose another uid", uid))
+			}
+
+			if j, ok := seen[uid]; ok {
+				diags.AddAttributeError(uidPath, "Duplicate Field UID",
+					fmt.Sprintf("field uid %#v is already used by fields[%d]", uid, j))
+			} else {
+				seen[uid] = i
+			}
+		}
+
+		if f.Unique.ValueBool() && f.Multiple.ValueBool() {
+			diags.AddAttributeError(path.Root("fields").AtListIndex(i).AtName("unique"), "Invalid Attribute Combination",
+				fmt.Sprintf("field %#v cannot be both unique and multiple", f.Uid.ValueString()))
+		}
+	}
+
+	return diags
+}
+
+func (r *GlobalFieldResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("fields"),
+			path.MatchRoot("schema_json"),
+		),
+	}
+}
+
+func (r *GlobalFieldResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to check when destroying
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		checkDestructiveChanges(ctx, req, resp)
+	}
+
+	// nor before the provider is configured
+	if resp.Diagnostics.HasError() || r.clients == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(r.clients.ValidatePlan(ctx, req.Plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// fields may be unknown until apply when built from other resources
+	var fieldsList types.List
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("fields"), &fieldsList)...)
+
+	if resp.Diagnostics.HasError() || fieldsList.IsUnknown() {
+		return
+	}
+
+	var fields []GlobalFieldSchemaFieldResourceModel
+
+	resp.Diagnostics.Append(fieldsList.ElementsAs(ctx, &fields, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the locales of the stack only matter to non_localizable fields, so
+	// plans without any do not call the API
+	if !anyNonLocalizable(fields) {
+		return
+	}
+
+	// the stack is not known yet when its api key comes from another resource
+	var stackAPIKey types.String
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("stack_api_key"), &stackAPIKey)...)
+
+	if resp.Diagnostics.HasError() || stackAPIKey.IsUnknown() {
+		return
+	}
+
+	client, dg := r.clients.ForStack(stackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	locales, err := getLocales(ctx, client)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Unable to read Locales", err, nil)
+		return
+	}
+
+	resp.Diagnostics.Append(validateNonLocalizable(fields, len(locales))...)
+}
+
+// fieldSummary holds the properties of a field whose changes can lose or
+// invalidate the data of existing entries; nil when unknown.
+type fieldSummary struct {
+	Uid       string
+	DataType  *string
+	Mandatory *bool
+	Multiple  *bool
+	Unique    *bool
+}
+
+// destructiveChange is a change to the fields of a GlobalField or ContentType
+// which loses or invalidates the data of existing entries.
+type destructiveChange struct {
+	// Index is the position of the changed field in the plan, or -1 when the
+	// field is removed.
+	Index int
+	// Attribute is the attribute of the field which changes, if any.
+	Attribute string
+	Message   string
+}
+
+// destructiveChanges compares the fields of the prior state with the planned
+// fields, matching them by uid, so renaming a field counts as removing it.
+func destructiveChanges(prior, planned []fieldSummary) []destructiveChange {
+	var changes []destructiveChange
+
+	plannedUIDs := make(map[string]bool, len(planned))
+	for _, f := range planned {
+		plannedUIDs[f.Uid] = true
+	}
+	priorByUID := make(map[string]fieldSummary, len(prior))
+	for _, f := range prior {
+		priorByUID[f.Uid] = f
+		if !plannedUIDs[f.Uid] {
+			changes = append(changes, destructiveChange{Index: -1,
+				Message: fmt.Sprintf("removing field %#v deletes its data from every entry", f.Uid)})
+		}
+	}
+
+	for i, f := range planned {
+		p, ok := priorByUID[f.Uid]
+		if !ok {
+			continue
+		}
+		if p.DataType != nil && f.DataType != nil && *p.DataType != *f.DataType {
+			changes = append(changes, destructiveChange{Index: i, Attribute: "data_type",
+				Message: fmt.Sprintf("changing the data_type of field %#v from %#v to %#v deletes its data from every entry", f.Uid, *p.DataType, *f.DataType)})
+		}
+		if turnedOn(p.Unique, f.Unique) {
+			changes = append(changes, destructiveChange{Index: i, Attribute: "unique",
+				Message: fmt.Sprintf("making field %#v unique invalidates the entries which share a value", f.Uid)})
+		}
+		if turnedOn(p.Mandatory, f.Mandatory) {
+			changes = append(changes, destructiveChange{Index: i, Attribute: "mandatory",
+				Message: fmt.Sprintf("making field %#v mandatory invalidates the entries without a value", f.Uid)})
+		}
+		if turnedOn(f.Multiple, p.Multiple) {
+			changes = append(changes, destructiveChange{Index: i, Attribute: "multiple",
+				Message: fmt.Sprintf("making field %#v single valued drops all but the first of its values from every entry", f.Uid)})
+		}
+	}
+
+	return changes
+}
+
+// turnedOn reports whether a flag goes from off to on.
+func turnedOn(before, after *bool) bool {
+	return before != nil && after != nil && !*before && *after
+}
+
+// summarizeFields summarizes the fields attribute.
+func summarizeFields(fields []GlobalFieldSchemaFieldResourceModel) []fieldSummary {
+	summaries := make([]fieldSummary, 0, len(fields))
+	for _, f := range fields {
+		if f.Uid.IsUnknown() {
+			continue
+		}
+		summaries = append(summaries, fieldSummary{
+			Uid:       f.Uid.ValueString(),
+			DataType:  knownString(f.DataType),
+			Mandatory: knownBool(f.Mandatory),
+			Multiple:  knownBool(f.Multiple),
+			Unique:    knownBool(f.Unique),
+		})
+	}
+	return summaries
+}
+
+// summarizeSchemaJSON summarizes the fields of a schema_json; false when the
+// schema is not known or not a list of fields.
+func summarizeSchemaJSON(v schemajson.Value) ([]fieldSummary, bool) {
+	if v.IsNull() || v.IsUnknown() {
+		return nil, false
+	}
+
+	var fields []schemaField
+	if err := json.Unmarshal([]byte(v.ValueString()), &fields); err != nil {
+		return nil, false
+	}
+
+	summaries := make([]fieldSummary, len(fields))
+	for i, f := range fields {
+		f := f
+		summaries[i] = fieldSummary{
+			Uid:       f.Uid,
+			DataType:  &f.DataType,
+			Mandatory: &f.Mandatory,
+			Multiple:  &f.Multiple,
+			Unique:    cschema.BoolPtr(f.Unique != nil && *f.Unique),
+		}
+	}
+	return summaries, true
+}
+
+// summarizeSchema summarizes the fields of a GlobalField set by either its
+// fields or its schema_json; false when they are not known.
+func summarizeSchema(ctx context.Context, fields types.List, schemaJSON schemajson.Value) ([]fieldSummary, bool, diag.Diagnostics) {
+	if fields.IsNull() {
+		summaries, ok := summarizeSchemaJSON(schemaJSON)
+		return summaries, ok, nil
+	}
+
+	var models []GlobalFieldSchemaFieldResourceModel
+	diags := fields.ElementsAs(ctx, &models, false)
+	if diags.HasError() {
+		return nil, false, diags
+	}
+	return summarizeFields(models), true, diags
+}
+
+func knownString(v types.String) *string {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+	return v.ValueStringPointer()
+}
+
+func knownBool(v types.Bool) *bool {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+	return v.ValueBoolPointer()
+}
+
+// checkDestructiveChanges reports each planned change to the fields which
+// loses or invalidates the data of existing entries: as an error, unless
+// allow_destructive_changes is set, when it is a warning.
+func checkDestructiveChanges(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var allow types.Bool
+	var priorFields, plannedFields types.List
+	var priorSchemaJSON, plannedSchemaJSON schemajson.Value
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("allow_destructive_changes"), &allow)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("fields"), &priorFields)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("fields"), &plannedFields)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("schema_json"), &priorSchemaJSON)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("schema_json"), &plannedSchemaJSON)...)
+
+	if resp.Diagnostics.HasError() || plannedFields.IsUnknown() {
+		return
+	}
+
+	// each side is summarized from whichever of fields and schema_json sets
+	// it, so that switching between them is checked too
+	prior, ok, dg := summarizeSchema(ctx, priorFields, priorSchemaJSON)
+	resp.Diagnostics.Append(dg...)
+	if !ok {
+		return
+	}
+	planned, ok, dg := summarizeSchema(ctx, plannedFields, plannedSchemaJSON)
+	resp.Diagnostics.Append(dg...)
+	if !ok {
+		return
+	}
+
+	fieldPath := func(destructiveChange) path.Path {
+		return path.Root("schema_json")
+	}
+	if !plannedFields.IsNull() {
+		fieldPath = func(c destructiveChange) path.Path {
+			if c.Index < 0 {
+				return path.Root("fields")
+			}
+			return path.Root("fields").AtListIndex(c.Index).AtName(c.Attribute)
+		}
+	}
+
+	reportDestructiveChanges(&resp.Diagnostics, "GlobalField", allow.ValueBool(), destructiveChanges(prior, planned), fieldPath)
+}
+
+// reportDestructiveChanges adds a diagnostic for each change to the schema of
+// a kind of resource, at the attribute given by fieldPath: an error, unless
+// allow_destructive_changes is set, when it is a warning.
+func reportDestructiveChanges(diags *diag.Diagnostics, kind string, allow bool, changes []destructiveChange, fieldPath func(destructiveChange) path.Path) {
+	for _, c := range changes {
+		if allow {
+			diags.AddAttributeWarning(fieldPath(c), "Destructive Schema Change",
+				fmt.Sprintf("Applying this plan will change the schema of the %s in a way that loses data: %s. allow_destructive_changes is set, so the change is allowed.", kind, c.Message))
+		} else {
+			diags.AddAttributeError(fieldPath(c), "Destructive Schema Change",
+				fmt.Sprintf("This plan would change the schema of the %s in a way that loses data: %s. Set allow_destructive_changes = true to apply such changes.", kind, c.Message))
+		}
+	}
+}
+
+// anyNonLocalizable reports whether some field sets non_localizable.
+func anyNonLocalizable(fields []GlobalFieldSchemaFieldResourceModel) bool {
+	for _, f := range fields {
+		if f.NonLocalizable.ValueBool() {
+			return true
+		}
+	}
+	return false
+}
+
+// validateNonLocalizable rejects non_localizable fields on a stack with a
+// single locale, where every field is trivially non-localizable and the API
+// refuses the flag.
+func validateNonLocalizable(fields []GlobalFieldSchemaFieldResourceModel, localeCount int) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if localeCount > 1 {
+		return diags
+	}
+
+	for i, f := range fields {
+		if f.NonLocalizable.ValueBool() {
+			diags.AddAttributeError(
+				path.Root("fields").AtListIndex(i).AtName("non_localizable"),
+				"Invalid Attribute Value",
+				fmt.Sprintf("field %#v cannot be non_localizable because the stack has a single locale", f.Uid.ValueString()),
+			)
+		}
+	}
+
+	return diags
+}
+
 func (r *GlobalFieldResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*management.Client)
+	clients, ok := req.ProviderData.(*clientFactory)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *management.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *clientFactory, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.clients = clients
 }
 
 func (r *GlobalFieldResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -360,21 +1110,38 @@ func (r *GlobalFieldResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	g := data.Export()
+	timeout, dg := data.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	created, err := r.client.CreateGlobalField(g)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create GlobalField %#v, got error: %s", data.UID.ValueString(), err))
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	g, dg := data.Export()
+	resp.Diagnostics.Append(dg...)
+
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// explicitly safe the computed fields (but not the optional fields; have to leave those as "unknown")
-	data.ID = types.StringValue(created.UID)
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	for i := range data.Fields {
-		data.Fields[i].Update(created.Schema[i])
+	created, err := createGlobalFieldJSON(ctx, client, g)
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to create GlobalField %#v", data.UID.ValueString()), err, data.errorPath)
+		return
 	}
 
+	// explicitly save the computed fields; a schema_json which the server only
+	// decorated with defaults is kept as planned by its semantic equality
+	resp.Diagnostics.Append(data.Update(created)...)
+
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
 	tflog.Trace(ctx, "created a GlobalField", map[string]interface{}{
@@ -395,13 +1162,48 @@ func (r *GlobalFieldResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	g, err := r.client.GetOneGlobalField(data.ID.ValueString())
+	timeout, dg := data.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	g, err := getOneGlobalFieldJSON(ctx, client, data.ID.ValueString())
+	if isNotFound(err) {
+		// deleted outside Terraform; planning to create it again
+		tflog.Warn(ctx, "GlobalField not found, removing it from state", map[string]interface{}{
+			"uid": data.UID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read GlobalField %#v, got error: %s", data.UID.ValueString(), err))
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to read GlobalField %#v", data.UID.ValueString()), err, nil)
 		return
 	}
 
-	data.Update(g)
+	resp.Diagnostics.Append(data.Update(g)...)
+
+	// settings of the resource rather than of the GlobalField, which are
+	// unset after an import
+	if data.AllowDestructiveChanges.IsNull() {
+		data.AllowDestructiveChanges = types.BoolValue(false)
+	}
+	if data.DeletionProtection.IsNull() {
+		data.DeletionProtection = types.BoolValue(false)
+	}
+	if data.ForceDelete.IsNull() {
+		data.ForceDelete = types.BoolValue(false)
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -417,14 +1219,52 @@ func (r *GlobalFieldResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
-	g := data.Export()
+	timeout, dg := data.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	_, err := r.client.UpdateGlobalField(g)
+	g, dg := data.Export()
+	resp.Diagnostics.Append(dg...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.clients.strictVersioning {
+		var version types.Int64
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("version"), &version)...)
+
+		current, err := getOneGlobalFieldJSON(ctx, client, data.ID.ValueString())
+		if err != nil {
+			addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to read GlobalField %#v", data.ID.ValueString()), err, nil)
+			return
+		}
+
+		resp.Diagnostics.Append(checkVersion("GlobalField", data.ID.ValueString(), version, current.Version)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	updated, err := updateGlobalFieldJSON(ctx, client, g)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update GlobalField %#v, got error: %s", data.ID.ValueString(), err))
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to update GlobalField %#v", data.ID.ValueString()), err, data.errorPath)
 		return
 	}
 
+	resp.Diagnostics.Append(data.Update(updated)...)
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -439,13 +1279,55 @@ func (r *GlobalFieldResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
-	err := r.client.DeleteGlobalField(data.ID.ValueString())
+	timeout, dg := data.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.DeletionProtection.ValueBool() && !data.ForceDelete.ValueBool() {
+		contentTypes, err := getContentTypesUsingGlobalField(ctx, client, data.ID.ValueString())
+		if err != nil {
+			addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to find the content types using GlobalField %#v", data.ID.ValueString()), err, nil)
+			return
+		}
+
+		if len(contentTypes) > 0 {
+			names := make([]string, len(contentTypes))
+			for i, ct := range contentTypes {
+				names[i] = fmt.Sprintf("%#v (%s)", ct.UID, ct.Title)
+			}
+			resp.Diagnostics.AddAttributeError(
+				path.Root("deletion_protection"),
+				"GlobalField In Use",
+				fmt.Sprintf("GlobalField %#v is protected from deletion and is used by the content types %s; deleting it would delete its data from their entries. "+
+					"Remove it from those content types first, or apply force_delete = true before destroying it.",
+					data.ID.ValueString(), strings.Join(names, ", ")),
+			)
+			return
+		}
+	}
+
+	err := deleteGlobalField(ctx, client, data.ID.ValueString(), data.ForceDelete.ValueBool())
+	if isNotFound(err) {
+		// already deleted outside Terraform
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete GlobalField %#v, got error: %s", data.ID.ValueString(), err))
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to delete GlobalField %#v", data.ID.ValueString()), err, nil)
 		return
 	}
 }
 
 func (r *GlobalFieldResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	importStatePassthroughStackScopedID(ctx, path.Root("id"), req, resp)
 }
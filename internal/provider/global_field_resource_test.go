@@ -0,0 +1,1037 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	cschema "github.com/davidalpert/go-contentstack/v1/schema"
+	"github.com/davidalpert/terraform-provider-contentstack/internal/contentstacktest"
+	"github.com/davidalpert/terraform-provider-contentstack/internal/tfutils/schemajson"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func testAccGlobalFieldResourceConfig(api *contentstacktest.Server, body string) string {
+	return testAccProviderConfig(api) + fmt.Sprintf(`
+resource "contentstack_global_field" "test" {
+%s
+}
+`, body)
+}
+
+// testAccCheckGlobalFieldDestroyed checks that destroying the configuration
+// deleted the global field from the stack.
+func testAccCheckGlobalFieldDestroyed(api *contentstacktest.Server, uid string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if _, ok := api.Get(contentstacktest.GlobalFields, uid); ok {
+			return fmt.Errorf("expected GlobalField %#v to be deleted", uid)
+		}
+		return nil
+	}
+}
+
+func TestAccGlobalFieldResource(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	created := `
+  uid         = "common_metadata"
+  title       = "Common Metadata"
+  description = "created by terraform"
+  fields = [
+    {
+      uid          = "headline"
+      display_name = "Headline"
+      data_type    = "text"
+    },
+    {
+      uid         = "summary"
+      data_type   = "text"
+      placeholder = "a sentence or two"
+      instruction = "shown in listings"
+    },
+    {
+      uid            = "priority"
+      data_type      = "number"
+      default_number = 1
+    }
+  ]
+`
+	updated := `
+  uid   = "common_metadata"
+  title = "Shared Metadata"
+
+  allow_destructive_changes = true
+
+  fields = [
+    {
+      uid          = "headline"
+      display_name = "Headline"
+      data_type    = "text"
+      mandatory    = true
+    },
+    {
+      uid         = "summary"
+      data_type   = "text"
+      placeholder = "one sentence"
+    },
+    {
+      uid            = "priority"
+      data_type      = "number"
+      default_number = 2
+    },
+    {
+      uid          = "featured"
+      data_type    = "boolean"
+      default_bool = false
+    }
+  ]
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckGlobalFieldDestroyed(api, "common_metadata"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccGlobalFieldResourceConfig(api, created),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "id", "common_metadata"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "title", "Common Metadata"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.#", "3"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.0.placeholder", ""),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.1.display_name", "summary"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.1.placeholder", "a sentence or two"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.1.instruction", "shown in listings"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.2.default_number", "1"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+			// ImportState testing
+			{
+				ResourceName:      "contentstack_global_field.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// the API leaves out empty and false properties; those match the
+			// defaults of the attributes and must not show up as changes
+			{
+				PreConfig: func() {
+					api.Modify(contentstacktest.GlobalFields, "common_metadata", func(r contentstacktest.Record) {
+						fields, _ := r["schema"].([]interface{})
+						for _, f := range fields {
+							f, _ := f.(map[string]interface{})
+							for _, k := range []string{"unique", "indexed", "inbuilt_model", "non_localizable"} {
+								delete(f, k)
+							}
+							metadata, _ := f["field_metadata"].(map[string]interface{})
+							if metadata["placeholder"] == "" {
+								delete(metadata, "placeholder")
+							}
+							if metadata["instruction"] == "" {
+								delete(metadata, "instruction")
+							}
+						}
+					})
+				},
+				Config:   testAccGlobalFieldResourceConfig(api, created),
+				PlanOnly: true,
+			},
+			// Update and Read testing
+			{
+				Config: testAccGlobalFieldResourceConfig(api, updated),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_global_field.test", plancheck.ResourceActionUpdate),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "title", "Shared Metadata"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "description", ""),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.#", "4"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.0.mandatory", "true"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.1.placeholder", "one sentence"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.1.instruction", ""),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.3.default_bool", "false"),
+				),
+			},
+			// Drift testing: a change made outside Terraform shows up in the plan
+			{
+				PreConfig: func() {
+					api.Modify(contentstacktest.GlobalFields, "common_metadata", func(r contentstacktest.Record) {
+						r["title"] = "Renamed in the web app"
+					})
+				},
+				Config:             testAccGlobalFieldResourceConfig(api, updated),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// and is reverted by the next apply
+			{
+				Config: testAccGlobalFieldResourceConfig(api, updated),
+				Check: func(s *terraform.State) error {
+					r, _ := api.Get(contentstacktest.GlobalFields, "common_metadata")
+					if r["title"] != "Shared Metadata" {
+						return fmt.Errorf("expected the title to be reverted, got %v", r["title"])
+					}
+					return nil
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccGlobalFieldResourceSchemaJSON(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	config := func(description string) string {
+		return testAccGlobalFieldResourceConfig(api, fmt.Sprintf(`
+  uid   = "seo"
+  title = "SEO"
+  schema_json = jsonencode([
+    {
+      uid          = "meta_title"
+      display_name = "Meta Title"
+      data_type    = "text"
+      field_metadata = {
+        description = %q
+      }
+    }
+  ])
+`, description))
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckGlobalFieldDestroyed(api, "seo"),
+		Steps: []resource.TestStep{
+			{
+				Config: config("title shown in search results"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "id", "seo"),
+					resource.TestCheckNoResourceAttr("contentstack_global_field.test", "fields"),
+				),
+			},
+			// the server decorating the schema with defaults is not a change
+			{
+				PreConfig: func() {
+					api.Modify(contentstacktest.GlobalFields, "seo", func(r contentstacktest.Record) {
+						fields, _ := r["schema"].([]interface{})
+						f, _ := fields[0].(map[string]interface{})
+						f["mandatory"] = false
+						f["unique"] = false
+					})
+				},
+				Config:   config("title shown in search results"),
+				PlanOnly: true,
+			},
+			{
+				Config: config("title shown in search engines"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_global_field.test", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			// an imported global field reads its schema into fields
+			{
+				ResourceName:            "contentstack_global_field.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"fields", "schema_json"},
+			},
+		},
+	})
+}
+
+func TestAccGlobalFieldResourceAPIErrors(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// the API rejects the second field; the error names it and its error code
+			{
+				Config: testAccGlobalFieldResourceConfig(api, `
+  uid   = "seo"
+  title = "SEO"
+  fields = [
+    {
+      uid          = "keywords"
+      data_type    = "text"
+      display_name = "Keywords"
+    },
+    {
+      uid          = "search_keywords"
+      data_type    = "text"
+      display_name = "Keywords"
+    },
+  ]
+`),
+				ExpectError: regexp.MustCompile(`(?s)Client Error \(error code 119\).*schema\.1\.display_name is not unique`),
+			},
+		},
+	})
+}
+
+func TestAccGlobalFieldResourceDeletedOutsideTerraform(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	config := testAccGlobalFieldResourceConfig(api, `
+  uid = "seo"
+  fields = [
+    {
+      uid       = "keywords"
+      data_type = "text"
+    },
+  ]
+`)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckGlobalFieldDestroyed(api, "seo"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			// a global field deleted in the UI is planned to be created again
+			{
+				PreConfig: func() {
+					api.Remove(contentstacktest.GlobalFields, "seo")
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_global_field.test", plancheck.ResourceActionCreate),
+					},
+				},
+			},
+			// and destroying one which is already gone succeeds
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						testAccRemoveBeforeDestroy(api, contentstacktest.GlobalFields, "seo"),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccGlobalFieldResourceStrictVersioning(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	config := func(title string) string {
+		return testAccProviderConfig(api, "  strict_versioning = true") + fmt.Sprintf(`
+resource "contentstack_global_field" "test" {
+  uid   = "seo"
+  title = %q
+  fields = [
+    {
+      uid       = "keywords"
+      data_type = "text"
+    },
+  ]
+}
+`, title)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckGlobalFieldDestroyed(api, "seo"),
+		Steps: []resource.TestStep{
+			{
+				Config: config("SEO"),
+				Check:  resource.TestCheckResourceAttr("contentstack_global_field.test", "version", "1"),
+			},
+			// an editor changes the global field between plan and apply
+			{
+				Config: config("Search"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						testAccPlanCheckFunc(func() error {
+							api.Modify(contentstacktest.GlobalFields, "seo", func(r contentstacktest.Record) {
+								r["description"] = "edited in the UI"
+							})
+							return nil
+						}),
+					},
+				},
+				ExpectError: regexp.MustCompile(`(?s)Conflicting Change.*state has version 1 and the stack has version 2`),
+			},
+			{
+				Config: config("Search"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "title", "Search"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "version", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGlobalFieldResourceDeletionProtection(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	config := func(settings string) string {
+		return testAccGlobalFieldResourceConfig(api, `
+  uid = "seo"
+  fields = [
+    {
+      uid       = "keywords"
+      data_type = "text"
+    },
+  ]
+`+settings)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckGlobalFieldDestroyed(api, "seo"),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`
+  deletion_protection = true
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "deletion_protection", "true"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "force_delete", "false"),
+					func(s *terraform.State) error {
+						api.Add(contentstacktest.ContentTypes, contentstacktest.Record{
+							"uid":   "blog_post",
+							"title": "Blog Post",
+							"schema": []interface{}{
+								map[string]interface{}{"uid": "seo", "data_type": "global_field", "reference_to": "seo"},
+							},
+						})
+						return nil
+					},
+				),
+			},
+			// a protected global field in use is not deleted
+			{
+				Config:      testAccProviderConfig(api),
+				ExpectError: regexp.MustCompile(`(?s)GlobalField In Use.*"blog_post" \(Blog Post\)`),
+			},
+			// unless forced
+			{
+				Config: config(`
+  deletion_protection = true
+  force_delete        = true
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_global_field.test", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			{
+				Config: testAccProviderConfig(api),
+			},
+		},
+	})
+}
+
+func TestAccGlobalFieldResourceDestructiveChanges(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	created := testAccGlobalFieldResourceConfig(api, `
+  uid = "seo"
+  fields = [
+    {
+      uid       = "keywords"
+      data_type = "text"
+    },
+    {
+      uid       = "summary"
+      data_type = "text"
+    },
+  ]
+`)
+	changed := func(settings string) string {
+		return testAccGlobalFieldResourceConfig(api, `
+  uid = "seo"
+  fields = [
+    {
+      uid       = "keywords"
+      data_type = "json"
+    },
+  ]
+`+settings)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckGlobalFieldDestroyed(api, "seo"),
+		Steps: []resource.TestStep{
+			{
+				Config: created,
+			},
+			// changes which lose the data of entries fail the plan
+			{
+				Config:      changed(""),
+				ExpectError: regexp.MustCompile(`(?s)Destructive Schema Change.*data_type of field "keywords" from "text" to "json".*Destructive Schema Change.*removing field "summary"`),
+			},
+			// unless allowed
+			{
+				Config: changed(`
+  allow_destructive_changes = true
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_global_field.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "allow_destructive_changes", "true"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.#", "1"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.0.data_type", "json"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGlobalFieldResourceDestructiveChangesSwitchingSchema(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	withFields := func(fields string) string {
+		return testAccGlobalFieldResourceConfig(api, `
+  uid = "seo"
+  fields = [
+`+fields+`
+  ]
+`)
+	}
+	withSchemaJSON := func(fields string) string {
+		return testAccGlobalFieldResourceConfig(api, `
+  uid = "seo"
+  schema_json = jsonencode([
+`+fields+`
+  ])
+`)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckGlobalFieldDestroyed(api, "seo"),
+		Steps: []resource.TestStep{
+			{
+				Config: withFields(`
+    { uid = "keywords", data_type = "text" },
+    { uid = "summary", data_type = "text" },
+`),
+			},
+			// switching from fields to schema_json compares the fields too
+			{
+				Config: withSchemaJSON(`
+    { uid = "keywords", display_name = "Keywords", data_type = "text" },
+`),
+				ExpectError: regexp.MustCompile(`(?s)Destructive Schema Change.*removing field "summary"`),
+			},
+			// and keeping them is no destructive change
+			{
+				Config: withSchemaJSON(`
+    { uid = "keywords", display_name = "Keywords", data_type = "text" },
+    { uid = "summary", display_name = "Summary", data_type = "text" },
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_global_field.test", plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+			// as is switching back
+			{
+				Config: withFields(`
+    { uid = "keywords", data_type = "json" },
+    { uid = "summary", data_type = "text" },
+`),
+				ExpectError: regexp.MustCompile(`(?s)Destructive Schema Change.*data_type of field "keywords" from "text" to "json"`),
+			},
+		},
+	})
+}
+
+func TestAccGlobalFieldResourceDateRange(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	config := func(dates string) string {
+		return testAccGlobalFieldResourceConfig(api, `
+  uid = "event"
+  fields = [
+    {
+      uid        = "starts_on"
+      data_type  = "isodate"
+      date_range = true
+`+dates+`
+    },
+  ]
+`)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckGlobalFieldDestroyed(api, "event"),
+		Steps: []resource.TestStep{
+			// a range without dates would read back as no range
+			{
+				Config:      config(""),
+				ExpectError: regexp.MustCompile(`(?s)Invalid Attribute Combination.*date_range.*start_date, end_date`),
+			},
+			{
+				Config: config(`      start_date = "2023-01-01"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.0.date_range", "true"),
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.0.start_date", "2023-01-01"),
+					resource.TestCheckNoResourceAttr("contentstack_global_field.test", "fields.0.end_date"),
+				),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccGlobalFieldResourceNonLocalizable(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	config := func(nonLocalizable bool) string {
+		return testAccGlobalFieldResourceConfig(api, fmt.Sprintf(`
+  uid = "product"
+  fields = [
+    {
+      uid             = "sku"
+      data_type       = "text"
+      indexed         = true
+      non_localizable = %t
+    },
+  ]
+`, nonLocalizable))
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckGlobalFieldDestroyed(api, "product"),
+		Steps: []resource.TestStep{
+			// without non_localizable fields the locales do not matter
+			{
+				Config: config(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.0.indexed", "true"),
+					func(s *terraform.State) error {
+						if n := api.Requests(http.MethodGet, contentstacktest.Locales.Path); n != 0 {
+							return fmt.Errorf("expected the locales not to be read, got %d requests", n)
+						}
+						return nil
+					},
+				),
+			},
+			// a stack with a single locale refuses non_localizable fields
+			{
+				Config:      config(true),
+				ExpectError: regexp.MustCompile(`field "sku" cannot be non_localizable because the stack has a single locale`),
+			},
+			// and one with several accepts them
+			{
+				PreConfig: func() {
+					api.Add(contentstacktest.Locales, contentstacktest.Record{"code": "fr-fr", "name": "French - France", "fallback_locale": "en-us"})
+				},
+				Config: config(true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_global_field.test", "fields.0.non_localizable", "true"),
+					func(s *terraform.State) error {
+						r, _ := api.Get(contentstacktest.GlobalFields, "product")
+						fields, _ := r["schema"].([]interface{})
+						f, _ := fields[0].(map[string]interface{})
+						if f["non_localizable"] != true || f["indexed"] != true {
+							return fmt.Errorf("expected the flags to be sent, got %v", f)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestGlobalFieldSchemaFieldDefaultValueRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]GlobalFieldSchemaFieldResourceModel{
+		"number": {
+			DataType:      types.StringValue("number"),
+			Uid:           types.StringValue("count"),
+			DefaultNumber: types.Float64Value(42),
+		},
+		"date": {
+			DataType:    types.StringValue("isodate"),
+			Uid:         types.StringValue("published"),
+			DefaultDate: types.StringValue("2023-06-01"),
+		},
+		"json": {
+			DataType:    types.StringValue("json"),
+			Uid:         types.StringValue("settings"),
+			DefaultJSON: types.StringValue(`{ "b": 1, "a": [true] }`),
+		},
+		"bool": {
+			DataType:    types.StringValue("boolean"),
+			Uid:         types.StringValue("enabled"),
+			DefaultBool: types.BoolValue(true),
+		},
+		"text": {
+			DataType:    types.StringValue("text"),
+			Uid:         types.StringValue("title"),
+			DefaultText: types.StringValue("untitled"),
+		},
+	}
+
+	for name, want := range tests {
+		name, want := name, want
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// simulate a round trip through the API
+			b, err := json.Marshal(want.Export())
+			if err != nil {
+				t.Fatalf("got unexpected error: %s", err)
+			}
+			var f schemaField
+			if err := json.Unmarshal(b, &f); err != nil {
+				t.Fatalf("got unexpected error: %s", err)
+			}
+
+			got := want
+			got.Update(f)
+
+			if diff := cmp.Diff(got.DefaultBool, want.DefaultBool); diff != "" {
+				t.Errorf("unexpected default_bool diff (+wanted, -got): %s", diff)
+			}
+			if diff := cmp.Diff(got.DefaultDate, want.DefaultDate); diff != "" {
+				t.Errorf("unexpected default_date diff (+wanted, -got): %s", diff)
+			}
+			if diff := cmp.Diff(got.DefaultJSON, want.DefaultJSON); diff != "" {
+				t.Errorf("unexpected default_json diff (+wanted, -got): %s", diff)
+			}
+			if diff := cmp.Diff(got.DefaultNumber, want.DefaultNumber); diff != "" {
+				t.Errorf("unexpected default_number diff (+wanted, -got): %s", diff)
+			}
+			if diff := cmp.Diff(got.DefaultText, want.DefaultText); diff != "" {
+				t.Errorf("unexpected default_text diff (+wanted, -got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestGlobalFieldSchemaFieldFlagsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []bool{true, false} {
+		value := value
+		t.Run(fmt.Sprint(value), func(t *testing.T) {
+			t.Parallel()
+
+			want := GlobalFieldSchemaFieldResourceModel{
+				DataType:       types.StringValue("text"),
+				Uid:            types.StringValue("sku"),
+				InbuiltModel:   types.BoolValue(value),
+				Indexed:        types.BoolValue(value),
+				NonLocalizable: types.BoolValue(value),
+			}
+
+			// simulate a round trip through the API
+			b, err := json.Marshal(want.Export())
+			if err != nil {
+				t.Fatalf("got unexpected error: %s", err)
+			}
+			var f schemaField
+			if err := json.Unmarshal(b, &f); err != nil {
+				t.Fatalf("got unexpected error: %s", err)
+			}
+
+			var got GlobalFieldSchemaFieldResourceModel
+			got.Update(f)
+
+			if diff := cmp.Diff(want.InbuiltModel, got.InbuiltModel); diff != "" {
+				t.Errorf("unexpected inbuilt_model diff (-wanted, +got): %s", diff)
+			}
+			if diff := cmp.Diff(want.Indexed, got.Indexed); diff != "" {
+				t.Errorf("unexpected indexed diff (-wanted, +got): %s", diff)
+			}
+			if diff := cmp.Diff(want.NonLocalizable, got.NonLocalizable); diff != "" {
+				t.Errorf("unexpected non_localizable diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+
+	// the API leaves out flags which are off
+	var got GlobalFieldSchemaFieldResourceModel
+	got.Update(schemaField{Field: cschema.Field{DataType: "text", Uid: "sku"}})
+	if got.InbuiltModel.ValueBool() || got.Indexed.ValueBool() || got.NonLocalizable.ValueBool() {
+		t.Errorf("expected missing flags to read as false, got %v, %v and %v", got.InbuiltModel, got.Indexed, got.NonLocalizable)
+	}
+}
+
+func TestValidateNonLocalizable(t *testing.T) {
+	t.Parallel()
+
+	field := func(uid string, nonLocalizable bool) GlobalFieldSchemaFieldResourceModel {
+		return GlobalFieldSchemaFieldResourceModel{
+			DataType:       types.StringValue("text"),
+			NonLocalizable: types.BoolValue(nonLocalizable),
+			Uid:            types.StringValue(uid),
+		}
+	}
+
+	type testCase struct {
+		fields      []GlobalFieldSchemaFieldResourceModel
+		localeCount int
+		expected    []path.Path
+	}
+	tests := map[string]testCase{
+		"single locale": {
+			fields:      []GlobalFieldSchemaFieldResourceModel{field("headline", false), field("sku", true), field("price", true)},
+			localeCount: 1,
+			expected: []path.Path{
+				path.Root("fields").AtListIndex(1).AtName("non_localizable"),
+				path.Root("fields").AtListIndex(2).AtName("non_localizable"),
+			},
+		},
+		"several locales": {
+			fields:      []GlobalFieldSchemaFieldResourceModel{field("headline", false), field("sku", true)},
+			localeCount: 2,
+		},
+		"no non_localizable field": {
+			fields:      []GlobalFieldSchemaFieldResourceModel{field("headline", false)},
+			localeCount: 1,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			diags := validateNonLocalizable(test.fields, test.localeCount)
+
+			if diags.ErrorsCount() != len(test.expected) {
+				t.Fatalf("expected %d errors, got: %s", len(test.expected), diags)
+			}
+			for i, d := range diags.Errors() {
+				withPath, _ := d.(diag.DiagnosticWithPath)
+				if withPath == nil || !withPath.Path().Equal(test.expected[i]) {
+					t.Errorf("expected an error on %s, got %v", test.expected[i], d)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	t.Parallel()
+
+	field := func(uid string) GlobalFieldSchemaFieldResourceModel {
+		return GlobalFieldSchemaFieldResourceModel{
+			DataType: types.StringValue("text"),
+			Uid:      types.StringValue(uid),
+		}
+	}
+
+	uniqueAndMultiple := field("tags_list")
+	uniqueAndMultiple.Unique = types.BoolValue(true)
+	uniqueAndMultiple.Multiple = types.BoolValue(true)
+
+	unique := func(uid string) GlobalFieldSchemaFieldResourceModel {
+		f := field(uid)
+		f.Unique = types.BoolValue(true)
+		return f
+	}
+
+	type testCase struct {
+		fields         []GlobalFieldSchemaFieldResourceModel
+		expectedErrors int
+	}
+	tests := map[string]testCase{
+		"valid": {
+			fields: []GlobalFieldSchemaFieldResourceModel{field("headline"), field("summary_2")},
+		},
+		"unknown uid": {
+			fields: []GlobalFieldSchemaFieldResourceModel{{Uid: types.StringUnknown()}, {Uid: types.StringUnknown()}},
+		},
+		"duplicate uid": {
+			fields:         []GlobalFieldSchemaFieldResourceModel{field("headline"), field("summary"), field("headline")},
+			expectedErrors: 1,
+		},
+		"reserved uid": {
+			fields:         []GlobalFieldSchemaFieldResourceModel{field("title"), field("created_at")},
+			expectedErrors: 2,
+		},
+		"invalid uid": {
+			fields:         []GlobalFieldSchemaFieldResourceModel{field("Headline"), field("1st"), field("sub-title")},
+			expectedErrors: 3,
+		},
+		"unique and multiple": {
+			fields:         []GlobalFieldSchemaFieldResourceModel{uniqueAndMultiple},
+			expectedErrors: 1,
+		},
+		// "unique on multiple fields" means a field which is both; several
+		// fields may each be unique
+		"several unique fields": {
+			fields: []GlobalFieldSchemaFieldResourceModel{unique("sku"), unique("slug"), field("title_text")},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			diags := validateFields(test.fields)
+
+			if diags.ErrorsCount() != test.expectedErrors {
+				t.Errorf("expected %d errors, got: %s", test.expectedErrors, diags)
+			}
+		})
+	}
+}
+
+func TestDestructiveChanges(t *testing.T) {
+	t.Parallel()
+
+	field := func(uid, dataType string) GlobalFieldSchemaFieldResourceModel {
+		return GlobalFieldSchemaFieldResourceModel{
+			DataType:  types.StringValue(dataType),
+			Mandatory: types.BoolValue(false),
+			Multiple:  types.BoolValue(false),
+			Uid:       types.StringValue(uid),
+			Unique:    types.BoolValue(false),
+		}
+	}
+	with := func(f GlobalFieldSchemaFieldResourceModel, modify func(f *GlobalFieldSchemaFieldResourceModel)) GlobalFieldSchemaFieldResourceModel {
+		modify(&f)
+		return f
+	}
+
+	headline, tags := field("headline", "text"), field("tags", "text")
+	multipleTags := with(tags, func(f *GlobalFieldSchemaFieldResourceModel) { f.Multiple = types.BoolValue(true) })
+
+	type testCase struct {
+		prior, planned []GlobalFieldSchemaFieldResourceModel
+		expected       []destructiveChange
+	}
+	tests := map[string]testCase{
+		"unchanged": {
+			prior:   []GlobalFieldSchemaFieldResourceModel{headline, tags},
+			planned: []GlobalFieldSchemaFieldResourceModel{tags, headline},
+		},
+		"added field": {
+			prior:   []GlobalFieldSchemaFieldResourceModel{headline},
+			planned: []GlobalFieldSchemaFieldResourceModel{headline, tags},
+		},
+		"relaxed field": {
+			prior: []GlobalFieldSchemaFieldResourceModel{with(headline, func(f *GlobalFieldSchemaFieldResourceModel) {
+				f.Mandatory = types.BoolValue(true)
+				f.Unique = types.BoolValue(true)
+			})},
+			planned: []GlobalFieldSchemaFieldResourceModel{headline},
+		},
+		"removed field": {
+			prior:   []GlobalFieldSchemaFieldResourceModel{headline, tags},
+			planned: []GlobalFieldSchemaFieldResourceModel{headline},
+			expected: []destructiveChange{
+				{Index: -1, Message: `removing field "tags" deletes its data from every entry`},
+			},
+		},
+		"renamed field": {
+			prior:   []GlobalFieldSchemaFieldResourceModel{headline},
+			planned: []GlobalFieldSchemaFieldResourceModel{field("title_text", "text")},
+			expected: []destructiveChange{
+				{Index: -1, Message: `removing field "headline" deletes its data from every entry`},
+			},
+		},
+		"changed data type": {
+			prior:   []GlobalFieldSchemaFieldResourceModel{headline, tags},
+			planned: []GlobalFieldSchemaFieldResourceModel{headline, field("tags", "json")},
+			expected: []destructiveChange{
+				{Index: 1, Attribute: "data_type", Message: `changing the data_type of field "tags" from "text" to "json" deletes its data from every entry`},
+			},
+		},
+		"unknown data type": {
+			prior:   []GlobalFieldSchemaFieldResourceModel{headline},
+			planned: []GlobalFieldSchemaFieldResourceModel{with(headline, func(f *GlobalFieldSchemaFieldResourceModel) { f.DataType = types.StringUnknown() })},
+		},
+		"tightened field": {
+			prior: []GlobalFieldSchemaFieldResourceModel{headline, multipleTags},
+			planned: []GlobalFieldSchemaFieldResourceModel{
+				with(headline, func(f *GlobalFieldSchemaFieldResourceModel) {
+					f.Mandatory = types.BoolValue(true)
+					f.Unique = types.BoolValue(true)
+				}),
+				tags,
+			},
+			expected: []destructiveChange{
+				{Index: 0, Attribute: "unique", Message: `making field "headline" unique invalidates the entries which share a value`},
+				{Index: 0, Attribute: "mandatory", Message: `making field "headline" mandatory invalidates the entries without a value`},
+				{Index: 1, Attribute: "multiple", Message: `making field "tags" single valued drops all but the first of its values from every entry`},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := destructiveChanges(summarizeFields(test.prior), summarizeFields(test.planned))
+
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Errorf("unexpected changes diff (-wanted, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestSummarizeSchemaJSON(t *testing.T) {
+	t.Parallel()
+
+	got, ok := summarizeSchemaJSON(schemajson.NewValue(`[{"uid":"tags","data_type":"text","multiple":true,"unique":true}]`))
+	if !ok {
+		t.Fatalf("expected the schema to be summarized")
+	}
+	text := "text"
+	want := []fieldSummary{{
+		Uid:       "tags",
+		DataType:  &text,
+		Mandatory: cschema.BoolPtr(false),
+		Multiple:  cschema.BoolPtr(true),
+		Unique:    cschema.BoolPtr(true),
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected summary diff (-wanted, +got): %s", diff)
+	}
+
+	for _, v := range []schemajson.Value{schemajson.NullValue(), schemajson.NewValue(`{"uid":"tags"}`)} {
+		if _, ok := summarizeSchemaJSON(v); ok {
+			t.Errorf("expected %s not to be summarized", v)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"github.com/davidalpert/terraform-provider-contentstack/internal/tfutils/schemajson"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// globalFieldResourceModelV0 describes the version 0 resource data model,
+// in which fields were stored as an unordered set.
+type globalFieldResourceModelV0 struct {
+	Description types.String                            `tfsdk:"description"`
+	Fields      []globalFieldSchemaFieldResourceModelV0 `tfsdk:"fields"`
+	ID          types.String                            `tfsdk:"id"`
+	Title       types.String                            `tfsdk:"title"`
+	UID         types.String                            `tfsdk:"uid"`
+}
+
+type globalFieldSchemaFieldResourceModelV0 struct {
+	DataType    types.String `tfsdk:"data_type"`
+	Description types.String `tfsdk:"description"`
+	DisplayName types.String `tfsdk:"display_name"`
+	DefaultText types.String `tfsdk:"default_text"`
+	DefaultBool types.Bool   `tfsdk:"default_bool"`
+	Format      types.String `tfsdk:"format"`
+	Mandatory   types.Bool   `tfsdk:"mandatory"`
+	Multiple    types.Bool   `tfsdk:"multiple"`
+	Placeholder types.String `tfsdk:"placeholder"`
+	Instruction types.String `tfsdk:"instruction"`
+	Uid         types.String `tfsdk:"uid"`
+	Unique      types.Bool   `tfsdk:"unique"`
+}
+
+// globalFieldSchemaV0 is a frozen copy of the version 0 schema; it must not
+// change as the current schema evolves.
+func globalFieldSchemaV0() *schema.Schema {
+	return &schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"description": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+			},
+			"fields": schema.SetNestedAttribute{
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"data_type":    schema.StringAttribute{Required: true},
+						"description":  schema.StringAttribute{Optional: true, Computed: true},
+						"default_bool": schema.BoolAttribute{Optional: true, Computed: true},
+						"default_text": schema.StringAttribute{Optional: true, Computed: true},
+						"display_name": schema.StringAttribute{Optional: true, Computed: true},
+						"format":       schema.StringAttribute{Optional: true},
+						"placeholder":  schema.StringAttribute{Optional: true, Computed: true},
+						"instruction":  schema.StringAttribute{Optional: true, Computed: true},
+						"uid":          schema.StringAttribute{Required: true},
+						"mandatory":    schema.BoolAttribute{Optional: true, Computed: true},
+						"multiple":     schema.BoolAttribute{Optional: true, Computed: true},
+						"unique":       schema.BoolAttribute{Optional: true, Computed: true},
+					},
+				},
+				Required: true,
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"title": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+			},
+			"uid": schema.StringAttribute{
+				Required: true,
+			},
+		},
+	}
+}
+
+func (r *GlobalFieldResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   globalFieldSchemaV0(),
+			StateUpgrader: upgradeGlobalFieldStateV0toV1,
+		},
+	}
+}
+
+// upgradeGlobalFieldStateV0toV1 converts the fields set into a list; a set has
+// no meaningful order so the next refresh will take the order from the server.
+func upgradeGlobalFieldStateV0toV1(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior globalFieldResourceModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	upgraded := GlobalFieldResourceModel{
+		AllowDestructiveChanges: types.BoolValue(false),
+		DeletionProtection:      types.BoolValue(false),
+		Description:             prior.Description,
+		Fields:                  make([]GlobalFieldSchemaFieldResourceModel, len(prior.Fields)),
+		ForceDelete:             types.BoolValue(false),
+		ID:                      prior.ID,
+		SchemaJSON:              schemajson.NullValue(),
+		StackAPIKey:             types.StringNull(),
+		Timeouts:                nullTimeouts(),
+		Title:                   prior.Title,
+		UID:                     prior.UID,
+		Version:                 types.Int64Null(),
+	}
+
+	for i, f := range prior.Fields {
+		upgraded.Fields[i] = GlobalFieldSchemaFieldResourceModel{
+			DataType:    f.DataType,
+			Description: f.Description,
+			DisplayName: f.DisplayName,
+			DefaultText: f.DefaultText,
+			DefaultBool: f.DefaultBool,
+			Format:      f.Format,
+			Mandatory:   f.Mandatory,
+			Multiple:    f.Multiple,
+			Placeholder: f.Placeholder,
+			Instruction: f.Instruction,
+			Uid:         f.Uid,
+			Unique:      f.Unique,
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, upgraded)...)
+}
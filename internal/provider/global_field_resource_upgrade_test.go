@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestUpgradeGlobalFieldStateV0toV1(t *testing.T) {
+	ctx := context.Background()
+
+	priorSchema := globalFieldSchemaV0()
+	priorType := priorSchema.Type().TerraformType(ctx).(tftypes.Object)
+	fieldType := priorType.AttributeTypes["fields"].(tftypes.Set).ElementType.(tftypes.Object)
+
+	field := tftypes.NewValue(fieldType, map[string]tftypes.Value{
+		"data_type":    tftypes.NewValue(tftypes.String, "text"),
+		"description":  tftypes.NewValue(tftypes.String, ""),
+		"default_bool": tftypes.NewValue(tftypes.Bool, nil),
+		"default_text": tftypes.NewValue(tftypes.String, "hello"),
+		"display_name": tftypes.NewValue(tftypes.String, "Title"),
+		"format":       tftypes.NewValue(tftypes.String, nil),
+		"placeholder":  tftypes.NewValue(tftypes.String, ""),
+		"instruction":  tftypes.NewValue(tftypes.String, ""),
+		"uid":          tftypes.NewValue(tftypes.String, "title"),
+		"mandatory":    tftypes.NewValue(tftypes.Bool, true),
+		"multiple":     tftypes.NewValue(tftypes.Bool, false),
+		"unique":       tftypes.NewValue(tftypes.Bool, false),
+	})
+
+	prior := tfsdk.State{
+		Schema: priorSchema,
+		Raw: tftypes.NewValue(priorType, map[string]tftypes.Value{
+			"description": tftypes.NewValue(tftypes.String, "created by terraform"),
+			"fields":      tftypes.NewValue(priorType.AttributeTypes["fields"], []tftypes.Value{field}),
+			"id":          tftypes.NewValue(tftypes.String, "common_metadata"),
+			"title":       tftypes.NewValue(tftypes.String, "Common Metadata"),
+			"uid":         tftypes.NewValue(tftypes.String, "common_metadata"),
+		}),
+	}
+
+	var schemaResp resource.SchemaResponse
+	(&GlobalFieldResource{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	resp := resource.UpgradeStateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+	upgradeGlobalFieldStateV0toV1(ctx, resource.UpgradeStateRequest{State: &prior}, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("got unexpected error: %s", resp.Diagnostics)
+	}
+
+	var got GlobalFieldResourceModel
+	if diags := resp.State.Get(ctx, &got); diags.HasError() {
+		t.Fatalf("got unexpected error: %s", diags)
+	}
+
+	if len(got.Fields) != 1 {
+		t.Fatalf("expected 1 field, got %d", len(got.Fields))
+	}
+
+	if diff := cmp.Diff(got.Fields[0].DefaultText, types.StringValue("hello")); diff != "" {
+		t.Errorf("unexpected diff (+wanted, -got): %s", diff)
+	}
+
+	if diff := cmp.Diff(got.Title, types.StringValue("Common Metadata")); diff != "" {
+		t.Errorf("unexpected diff (+wanted, -got): %s", diff)
+	}
+}
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// apiLogSubsystem is the tflog subsystem of the API requests; its level
+	// defaults to TF_LOG_PROVIDER and can be set with TF_LOG_PROVIDER_CONTENTSTACK_API.
+	apiLogSubsystem = "contentstack_api"
+
+	defaultLogBodyMaxBytes = 1024
+)
+
+// maskedLogHeaders are the request headers which carry credentials.
+var maskedLogHeaders = []string{"api_key", "authorization", "authtoken"}
+
+// maskedLogBodyValues matches the secrets sent and returned in request and
+// response bodies, as JSON properties or form values; the closing quote is
+// optional as a truncated body may end in the middle of a value.
+var maskedLogBodyValues = regexp.MustCompile(
+	`"(api_key|authtoken|password|tfa_token|access_token|refresh_token|client_secret)"\s*:\s*"[^"]*"?` +
+		`|\b(client_secret|refresh_token|access_token)=[^&\s]*`,
+)
+
+func logHeaderField(name string) string {
+	return "http.request.header." + strings.ToLower(name)
+}
+
+// loggingTransport logs every API request and response at DEBUG level in the
+// apiLogSubsystem, with credentials masked and bodies truncated.
+type loggingTransport struct {
+	next http.RoundTripper
+	// maxBodyBytes is the most of each body which is logged; 0 leaves bodies out
+	maxBodyBytes int
+}
+
+func newLoggingTransport(next http.RoundTripper, maxBodyBytes int) *loggingTransport {
+	return &loggingTransport{next: next, maxBodyBytes: maxBodyBytes}
+}
+
+// logContext sets up the API log subsystem on the context of a request, which
+// carries the provider logger of the Terraform operation it belongs to.
+func logContext(ctx context.Context) context.Context {
+	ctx = tflog.NewSubsystem(ctx, apiLogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_CONTENTSTACK_API"))
+
+	keys := make([]string, len(maskedLogHeaders))
+	for i, h := range maskedLogHeaders {
+		keys[i] = logHeaderField(h)
+	}
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, apiLogSubsystem, keys...)
+	ctx = tflog.SubsystemMaskAllFieldValuesRegexes(ctx, apiLogSubsystem, maskedLogBodyValues)
+
+	return ctx
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := logContext(req.Context())
+
+	fields := map[string]interface{}{
+		"http.method": req.Method,
+		"http.url":    req.URL.String(),
+	}
+	for name, values := range req.Header {
+		fields[logHeaderField(name)] = strings.Join(values, ", ")
+	}
+	if t.maxBodyBytes > 0 && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil && body != nil {
+			b, _ := io.ReadAll(body)
+			_ = body.Close()
+			fields["http.request.body"] = truncateLogBody(b, t.maxBodyBytes)
+		}
+	}
+	tflog.SubsystemDebug(ctx, apiLogSubsystem, "sending API request", fields)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		tflog.SubsystemDebug(ctx, apiLogSubsystem, "API request failed", map[string]interface{}{
+			"http.method":      req.Method,
+			"http.url":         req.URL.String(),
+			"http.duration_ms": duration.Milliseconds(),
+			"error":            err.Error(),
+		})
+		return resp, err
+	}
+
+	fields = map[string]interface{}{
+		"http.method":               req.Method,
+		"http.url":                  req.URL.String(),
+		"http.duration_ms":          duration.Milliseconds(),
+		"http.response.status_code": resp.StatusCode,
+	}
+	if t.maxBodyBytes > 0 && resp.Body != nil {
+		b, readErr := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		// hand the body on as if it had not been read
+		resp.Body = io.NopCloser(bytes.NewReader(b))
+		if readErr != nil {
+			return nil, readErr
+		}
+		fields["http.response.body"] = truncateLogBody(b, t.maxBodyBytes)
+	}
+	tflog.SubsystemDebug(ctx, apiLogSubsystem, "received API response", fields)
+
+	return resp, nil
+}
+
+// truncateLogBody returns at most max bytes of a body for logging.
+func truncateLogBody(b []byte, max int) string {
+	if len(b) <= max {
+		return string(b)
+	}
+	return fmt.Sprintf("%s... (%d more bytes)", b[:max], len(b)-max)
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestLoggingTransportMasksCredentials(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"user":{"email":"user@example.com","authtoken":"session-token"},"notice":"` + strings.Repeat("x", 100) + `"}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v3/user-session", strings.NewReader(`{"user":{"email":"user@example.com","password":"secret"}}`))
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	req.Header.Set("api_key", "stack-api-key")
+	req.Header.Set("Authorization", "management-token")
+
+	resp, err := newLoggingTransport(http.DefaultTransport, 64).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(resp.Body); err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	if !strings.Contains(body.String(), "session-token") {
+		t.Errorf("expected the response body to be passed on unchanged, got %q", body.String())
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected a request and a response log entry, got %d: %v", len(entries), entries)
+	}
+
+	for _, secret := range []string{"stack-api-key", "management-token", "secret", "session-token"} {
+		if strings.Contains(output.String(), secret) {
+			t.Errorf("expected %q to be masked, got logs: %s", secret, output.String())
+		}
+	}
+
+	if got := entries[0]["http.request.header.api_key"]; got != "***" {
+		t.Errorf("expected api_key header to be masked, got %v", got)
+	}
+	if got := entries[1]["http.response.status_code"]; got != float64(http.StatusOK) {
+		t.Errorf("expected status code 200, got %v", got)
+	}
+	if got, ok := entries[1]["http.response.body"].(string); !ok || !strings.HasSuffix(got, "more bytes)") {
+		t.Errorf("expected a truncated response body, got %v", entries[1]["http.response.body"])
+	}
+}
+
+func TestLoggingTransportWithoutRequestBody(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, server.URL+"/v3/environments/staging", nil)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	// some clients set GetBody even when there is no body to replay
+	req.GetBody = func() (io.ReadCloser, error) { return nil, nil }
+
+	resp, err := newLoggingTransport(http.DefaultTransport, 64).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected a request and a response log entry, got %d: %v", len(entries), entries)
+	}
+	if _, ok := entries[0]["http.request.body"]; ok {
+		t.Errorf("expected no request body to be logged, got %v", entries[0]["http.request.body"])
+	}
+}
+
+func TestTruncateLogBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		body string
+		max  int
+		want string
+	}{
+		{body: "", max: 4, want: ""},
+		{body: "abcd", max: 4, want: "abcd"},
+		{body: "abcdef", max: 4, want: "abcd... (2 more bytes)"},
+	}
+
+	for _, tt := range tests {
+		if got := truncateLogBody([]byte(tt.body), tt.max); got != tt.want {
+			t.Errorf("truncateLogBody(%q, %d) = %q, want %q", tt.body, tt.max, got, tt.want)
+		}
+	}
+}
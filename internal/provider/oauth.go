@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// oauthTokenExpiryLeeway refreshes an access token this long before it expires
+// so that a request never goes out with a token which lapses in flight.
+const oauthTokenExpiryLeeway = time.Minute
+
+type oauthCredentials struct {
+	ClientID      string
+	ClientSecret  string
+	RefreshToken  string
+	AppUID        string
+	TokenEndpoint string
+}
+
+type oauthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	TokenType    string `json:"token_type"`
+}
+
+// oauthTokenSource exchanges an OAuth app's refresh token for access tokens,
+// refreshing them as they expire so that long applies keep working.
+type oauthTokenSource struct {
+	credentials oauthCredentials
+	httpClient  *http.Client
+	now         func() time.Time
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func newOAuthTokenSource(credentials oauthCredentials) *oauthTokenSource {
+	return &oauthTokenSource{
+		credentials: credentials,
+		httpClient:  http.DefaultClient,
+		now:         time.Now,
+	}
+}
+
+// Token returns a valid access token, refreshing it first when needed.
+func (s *oauthTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && s.now().Add(oauthTokenExpiryLeeway).Before(s.expiresAt) {
+		return s.accessToken, nil
+	}
+
+	return s.refresh(ctx)
+}
+
+// Invalidate forgets the current access token so that the next request
+// refreshes it, e.g. after the API rejects it.
+func (s *oauthTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accessToken = ""
+}
+
+func (s *oauthTokenSource) refresh(ctx context.Context) (string, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {s.credentials.ClientID},
+		"client_secret": {s.credentials.ClientSecret},
+		"refresh_token": {s.credentials.RefreshToken},
+	}
+	if s.credentials.AppUID != "" {
+		form.Set("app_uid", s.credentials.AppUID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.credentials.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("calling %#v  %s: %s", s.credentials.TokenEndpoint, resp.Status, string(body))
+	}
+
+	var r oauthTokenResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return "", fmt.Errorf("calling %#v: %w", s.credentials.TokenEndpoint, err)
+	}
+	if r.AccessToken == "" {
+		return "", fmt.Errorf("calling %#v: no access_token in response", s.credentials.TokenEndpoint)
+	}
+
+	s.accessToken = r.AccessToken
+	s.expiresAt = s.now().Add(time.Duration(r.ExpiresIn) * time.Second)
+	// refresh tokens may be rotated on every exchange
+	if r.RefreshToken != "" {
+		s.credentials.RefreshToken = r.RefreshToken
+	}
+
+	return s.accessToken, nil
+}
+
+// useOAuth switches the client to OAuth bearer token authentication, fetching
+// a fresh access token from the token source before each request.
+func useOAuth(c *apiClient, source *oauthTokenSource) {
+	rc := c.resty
+	rc.Header.Del("Authorization")
+	rc.OnBeforeRequest(func(_ *resty.Client, r *resty.Request) error {
+		token, err := source.Token(r.Context())
+		if err != nil {
+			return fmt.Errorf("refreshing OAuth access token: %w", err)
+		}
+		r.SetHeader("Authorization", "Bearer "+token)
+		return nil
+	})
+	rc.OnAfterResponse(func(_ *resty.Client, r *resty.Response) error {
+		if r.StatusCode() == http.StatusUnauthorized {
+			source.Invalidate()
+		}
+		return nil
+	})
+}
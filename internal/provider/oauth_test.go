@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davidalpert/go-contentstack/v1/management"
+)
+
+func TestOAuthTokenSourceRefreshesBearerToken(t *testing.T) {
+	var mu sync.Mutex
+	exchanges := 0
+	refreshTokensSeen := []string{}
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("client_id") != "client" || r.PostForm.Get("client_secret") != "secret" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		exchanges++
+		refreshTokensSeen = append(refreshTokensSeen, r.PostForm.Get("refresh_token"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"access-%d","refresh_token":"refresh-%d","expires_in":3600,"token_type":"Bearer"}`, exchanges, exchanges)
+	}))
+	defer tokenServer.Close()
+
+	var authorization string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"locales":[]}`))
+	}))
+	defer apiServer.Close()
+
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	source := newOAuthTokenSource(oauthCredentials{
+		ClientID:      "client",
+		ClientSecret:  "secret",
+		RefreshToken:  "refresh-0",
+		TokenEndpoint: tokenServer.URL,
+	})
+	source.now = func() time.Time { return now }
+
+	client := newAPIClient(&management.Configuration{Host: apiServer.URL, Key: "api-key", Token: "management-token"})
+	useOAuth(client, source)
+
+	if _, err := getLocales(context.Background(), client); err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	if authorization != "Bearer access-1" {
+		t.Errorf("expected Authorization %q, got %q", "Bearer access-1", authorization)
+	}
+
+	// the cached token is reused while it is valid
+	now = now.Add(30 * time.Minute)
+	if _, err := getLocales(context.Background(), client); err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	if authorization != "Bearer access-1" {
+		t.Errorf("expected Authorization %q, got %q", "Bearer access-1", authorization)
+	}
+
+	// and refreshed with the rotated refresh token once it nears expiry
+	now = now.Add(30 * time.Minute)
+	if _, err := getLocales(context.Background(), client); err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	if authorization != "Bearer access-2" {
+		t.Errorf("expected Authorization %q, got %q", "Bearer access-2", authorization)
+	}
+
+	if exchanges != 2 {
+		t.Errorf("expected 2 token exchanges, got %d", exchanges)
+	}
+	if want := []string{"refresh-0", "refresh-1"}; fmt.Sprint(refreshTokensSeen) != fmt.Sprint(want) {
+		t.Errorf("expected refresh tokens %v, got %v", want, refreshTokensSeen)
+	}
+}
+
+func TestOAuthTokenSourceRejectedCredentials(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+	}))
+	defer tokenServer.Close()
+
+	source := newOAuthTokenSource(oauthCredentials{
+		ClientID:      "client",
+		ClientSecret:  "secret",
+		RefreshToken:  "revoked",
+		TokenEndpoint: tokenServer.URL,
+	})
+
+	if _, err := source.Token(context.Background()); err == nil {
+		t.Fatal("expected error, got no error")
+	}
+}
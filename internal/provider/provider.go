@@ -2,9 +2,20 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"github.com/davidalpert/go-contentstack/v1/management"
+	"github.com/davidalpert/terraform-provider-contentstack/internal/tfutils/durationvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
@@ -15,6 +26,7 @@ import (
 
 // Ensure ContentStackProvider satisfies various provider interfaces.
 var _ provider.Provider = &ContentStackProvider{}
+var _ provider.ProviderWithConfigValidators = &ContentStackProvider{}
 
 // ContentStackProvider defines the provider implementation.
 type ContentStackProvider struct {
@@ -24,14 +36,40 @@ type ContentStackProvider struct {
 	version string
 	commit  string
 	date    string
+
+	// wrapTransport lets tests record or replay the API traffic of the
+	// provider; nil in production.
+	wrapTransport func(http.RoundTripper) http.RoundTripper
 }
 
 // ContentStackProviderModel describes the provider data model.
 type ContentStackProviderModel struct {
-	Host            types.String `tfsdk:"host"`
-	ApiKey          types.String `tfsdk:"api_key"`
-	ManagementToken types.String `tfsdk:"management_token"`
-	Debug           types.Bool   `tfsdk:"debug"`
+	Host               types.String  `tfsdk:"host"`
+	Region             types.String  `tfsdk:"region"`
+	ApiKey             types.String  `tfsdk:"api_key"`
+	ManagementToken    types.String  `tfsdk:"management_token"`
+	Authtoken          types.String  `tfsdk:"authtoken"`
+	Email              types.String  `tfsdk:"email"`
+	Password           types.String  `tfsdk:"password"`
+	TfaToken           types.String  `tfsdk:"tfa_token"`
+	OAuthClientID      types.String  `tfsdk:"oauth_client_id"`
+	OAuthClientSecret  types.String  `tfsdk:"oauth_client_secret"`
+	OAuthRefreshToken  types.String  `tfsdk:"oauth_refresh_token"`
+	OAuthAppUID        types.String  `tfsdk:"oauth_app_uid"`
+	OAuthTokenEndpoint types.String  `tfsdk:"oauth_token_endpoint"`
+	RequestsPerSecond  types.Float64 `tfsdk:"requests_per_second"`
+	RequestTimeout     types.String  `tfsdk:"request_timeout"`
+	ProxyURL           types.String  `tfsdk:"proxy_url"`
+	CACertFile         types.String  `tfsdk:"ca_cert_file"`
+	CACertPEM          types.String  `tfsdk:"ca_cert_pem"`
+	ClientCert         types.String  `tfsdk:"client_cert"`
+	ClientKey          types.String  `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool    `tfsdk:"insecure_skip_verify"`
+	MaxRetries         types.Int64   `tfsdk:"max_retries"`
+	RetryMaxWait       types.String  `tfsdk:"retry_max_wait"`
+	LogBodyMaxBytes    types.Int64   `tfsdk:"log_body_max_bytes"`
+	StrictVersioning   types.Bool    `tfsdk:"strict_versioning"`
+	Debug              types.Bool    `tfsdk:"debug"`
 }
 
 func (p *ContentStackProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -44,14 +82,22 @@ func (p *ContentStackProvider) Schema(ctx context.Context, req provider.SchemaRe
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
 				Optional: true,
-				MarkdownDescription: `Base URL
+				MarkdownDescription: `Base URL of the Content Management API; prefer ` + "`region`" + ` unless the stack uses a custom host. Can also be set with the CONTENTSTACK_HOST environment variable.
 - US (North America, or NA): https://api.contentstack.io/
 - Europe (EU): https://eu-api.contentstack.com/
 - Azure NA: https://azure-na-api.contentstack.com/
 `,
 			},
+			"region": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Contentstack region which hosts the stack, one of: `" + strings.Join(regionNames(), "`, `") + "`. " +
+					"Resolves the Content Management API host and conflicts with `host`. Can also be set with the CONTENTSTACK_REGION environment variable.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(regionNames()...),
+				},
+			},
 			"api_key": schema.StringAttribute{
-				MarkdownDescription: "An API Key which uniquely identifies the stack which this provider will configure.",
+				MarkdownDescription: "An API Key which uniquely identifies the stack which this provider will configure by default; resources and data sources can manage other stacks with `stack_api_key` when the provider uses user or OAuth credentials.",
 				Optional:            true,
 				Sensitive:           true,
 			},
@@ -60,14 +106,153 @@ func (p *ContentStackProvider) Schema(ctx context.Context, req provider.SchemaRe
 				Optional:            true,
 				Sensitive:           true,
 			},
-			"debug": schema.BoolAttribute{
-				MarkdownDescription: "enable debug logs for the ContentStack API client",
+			"authtoken": schema.StringAttribute{
+				MarkdownDescription: "A user authtoken to authenticate with instead of a management token; authtokens carry the permissions of the user who owns them. Can also be set with the CONTENTSTACK_AUTHTOKEN environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"email": schema.StringAttribute{
+				MarkdownDescription: "Email address of a user to log in as instead of using a management token; requires `password`. The session is logged out when the provider shuts down. Can also be set with the CONTENTSTACK_EMAIL environment variable.",
+				Optional:            true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Password of the user given by `email`. Can also be set with the CONTENTSTACK_PASSWORD environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"tfa_token": schema.StringAttribute{
+				MarkdownDescription: "Two-factor authentication token to log in with when the user given by `email` has 2FA enabled. Can also be set with the CONTENTSTACK_TFA_TOKEN environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"oauth_client_id": schema.StringAttribute{
+				MarkdownDescription: "Client ID of a Contentstack OAuth app to authenticate with instead of a management token; requires `oauth_client_secret` and `oauth_refresh_token`. Access tokens are exchanged and refreshed automatically. Can also be set with the CONTENTSTACK_OAUTH_CLIENT_ID environment variable.",
+				Optional:            true,
+			},
+			"oauth_client_secret": schema.StringAttribute{
+				MarkdownDescription: "Client secret of the OAuth app given by `oauth_client_id`. Can also be set with the CONTENTSTACK_OAUTH_CLIENT_SECRET environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"oauth_refresh_token": schema.StringAttribute{
+				MarkdownDescription: "Refresh token issued to the OAuth app, exchanged for short-lived access tokens. Can also be set with the CONTENTSTACK_OAUTH_REFRESH_TOKEN environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"oauth_app_uid": schema.StringAttribute{
+				MarkdownDescription: "UID of the OAuth app given by `oauth_client_id`. Can also be set with the CONTENTSTACK_OAUTH_APP_UID environment variable.",
+				Optional:            true,
+			},
+			"oauth_token_endpoint": schema.StringAttribute{
+				MarkdownDescription: "URL of the OAuth token endpoint; defaults to the endpoint of the configured `region` and is required with a custom `host`. Can also be set with the CONTENTSTACK_OAUTH_TOKEN_ENDPOINT environment variable.",
+				Optional:            true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: fmt.Sprintf("Most requests per second which the provider sends across all resources, data sources and stacks, so that parallel operations are throttled before Contentstack rate limits them; `0` disables throttling. Defaults to %d, the published Content Management API limit. Can also be set with the CONTENTSTACK_REQUESTS_PER_SECOND environment variable.", defaultRequestsPerSecond),
+				Optional:            true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Longest time a single request to the API may take, as a duration such as `30s`; each retry gets the full time again and `0s` disables the timeout. Defaults to `%s`. Can also be set with the CONTENTSTACK_REQUEST_TIMEOUT environment variable.", defaultRequestTimeout),
+				Optional:            true,
+				Validators: []validator.String{
+					durationvalidator.Valid(),
+				},
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "URL of an HTTP(S) proxy to send API requests through, such as `http://proxy.example.com:3128`; defaults to the HTTPS_PROXY and NO_PROXY environment variables. Can also be set with the CONTENTSTACK_PROXY_URL environment variable.",
+				Optional:            true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM file of CA certificates to trust in addition to the system roots, e.g. for a TLS-inspecting proxy; conflicts with `ca_cert_pem`. Can also be set with the CONTENTSTACK_CA_CERT_FILE environment variable.",
+				Optional:            true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded CA certificates to trust in addition to the system roots; conflicts with `ca_cert_file`. Can also be set with the CONTENTSTACK_CA_CERT_PEM environment variable.",
+				Optional:            true,
+			},
+			"client_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded client certificate for mutual TLS; requires `client_key`. Can also be set with the CONTENTSTACK_CLIENT_CERT environment variable.",
+				Optional:            true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded private key of `client_cert`. Can also be set with the CONTENTSTACK_CLIENT_KEY environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Skip verification of the API server certificate. Only use this to diagnose TLS problems; prefer `ca_cert_file` or `ca_cert_pem`. Can also be set with the CONTENTSTACK_INSECURE_SKIP_VERIFY environment variable.",
+				Optional:            true,
+			},
+			"strict_versioning": schema.BoolAttribute{
+				MarkdownDescription: "Refuse to update an environment, global field or content type which has been changed outside Terraform since it was last read, rather than overwriting the change; compares the `version` in the state with the version on the stack. The check is best-effort: the API has no conditional update, so the version is read just before the update and a change made in between is still overwritten. Defaults to false. Can also be set with the CONTENTSTACK_STRICT_VERSIONING environment variable.",
 				Optional:            true,
 			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Number of times a request which was rate limited (429), or a GET, PUT or DELETE which failed with a server (5xx) or network error, is retried, with exponential backoff; a POST which failed that way is not retried as it may have been applied. `0` disables retries. Defaults to %d. Can also be set with the CONTENTSTACK_MAX_RETRIES environment variable.", defaultMaxRetries),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_max_wait": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Longest wait between retries as a duration such as `30s`; also caps the wait asked for by a `Retry-After` header. Defaults to `%s`. Can also be set with the CONTENTSTACK_RETRY_MAX_WAIT environment variable.", defaultRetryMaxWait),
+				Optional:            true,
+				Validators: []validator.String{
+					durationvalidator.Valid(),
+				},
+			},
+			"log_body_max_bytes": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Most bytes of each request and response body to include in the debug logs of API requests; `0` leaves bodies out. Defaults to %d. Can also be set with the CONTENTSTACK_LOG_BODY_MAX_BYTES environment variable.", defaultLogBodyMaxBytes),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"debug": schema.BoolAttribute{
+				MarkdownDescription: "Has no effect; API requests are always logged at the DEBUG level of the `contentstack_api` log subsystem, with credentials masked.",
+				DeprecationMessage: "API requests are now logged through Terraform with credentials masked; enable them with TF_LOG_PROVIDER=DEBUG, " +
+					"or TF_LOG_PROVIDER_CONTENTSTACK_API=DEBUG for the API requests only, and remove the debug attribute.",
+				Optional: true,
+			},
 		},
 	}
 }
 
+func (p *ContentStackProvider) ConfigValidators(ctx context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		providervalidator.Conflicting(
+			path.MatchRoot("host"),
+			path.MatchRoot("region"),
+		),
+		providervalidator.Conflicting(
+			path.MatchRoot("management_token"),
+			path.MatchRoot("authtoken"),
+			path.MatchRoot("email"),
+			path.MatchRoot("oauth_client_id"),
+		),
+		providervalidator.RequiredTogether(
+			path.MatchRoot("email"),
+			path.MatchRoot("password"),
+		),
+		providervalidator.Conflicting(
+			path.MatchRoot("ca_cert_file"),
+			path.MatchRoot("ca_cert_pem"),
+		),
+		providervalidator.RequiredTogether(
+			path.MatchRoot("client_cert"),
+			path.MatchRoot("client_key"),
+		),
+		providervalidator.RequiredTogether(
+			path.MatchRoot("oauth_client_id"),
+			path.MatchRoot("oauth_client_secret"),
+			path.MatchRoot("oauth_refresh_token"),
+		),
+	}
+}
+
 func (p *ContentStackProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var data ContentStackProviderModel
 
@@ -84,47 +269,178 @@ func (p *ContentStackProvider) Configure(ctx context.Context, req provider.Confi
 	// with Terraform configuration value if set.
 
 	host := os.Getenv("CONTENTSTACK_HOST")
+	region := os.Getenv("CONTENTSTACK_REGION")
 	apiKey := os.Getenv("CONTENTSTACK_API_KEY")
 	managementToken := os.Getenv("CONTENTSTACK_MANAGEMENT_TOKEN")
+	authtoken := os.Getenv("CONTENTSTACK_AUTHTOKEN")
+	email := os.Getenv("CONTENTSTACK_EMAIL")
+	password := os.Getenv("CONTENTSTACK_PASSWORD")
+	tfaToken := os.Getenv("CONTENTSTACK_TFA_TOKEN")
+	oauth := oauthCredentials{
+		ClientID:      os.Getenv("CONTENTSTACK_OAUTH_CLIENT_ID"),
+		ClientSecret:  os.Getenv("CONTENTSTACK_OAUTH_CLIENT_SECRET"),
+		RefreshToken:  os.Getenv("CONTENTSTACK_OAUTH_REFRESH_TOKEN"),
+		AppUID:        os.Getenv("CONTENTSTACK_OAUTH_APP_UID"),
+		TokenEndpoint: os.Getenv("CONTENTSTACK_OAUTH_TOKEN_ENDPOINT"),
+	}
 
+	// a host or region in the configuration wins over either environment variable
 	if !data.Host.IsNull() {
 		host = data.Host.ValueString()
+		region = ""
+	} else if !data.Region.IsNull() {
+		host = ""
+		region = data.Region.ValueString()
 	}
 
+	if host != "" && region != "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("region"),
+			"Conflicting ContentStack API Host and Region",
+			"The provider cannot create the ContentStack API client as both CONTENTSTACK_HOST and CONTENTSTACK_REGION are set. "+
+				"Set only one of them, or set the host or region value in the configuration.",
+		)
+	} else if region != "" {
+		regionHost, ok := hostForRegion(region)
+		if !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("region"),
+				"Unknown ContentStack Region",
+				fmt.Sprintf("The provider cannot create the ContentStack API client as %#v is not a known ContentStack region. ", region)+
+					"Set the region value in the configuration or the CONTENTSTACK_REGION environment variable to one of: "+
+					strings.Join(regionNames(), ", ")+".",
+			)
+		}
+		host = regionHost
+	}
+
+	host = strings.TrimRight(strings.TrimSpace(host), "/")
+
 	if !data.ApiKey.IsNull() {
 		apiKey = data.ApiKey.ValueString()
 	}
 
-	if !data.ManagementToken.IsNull() {
+	// a credential in the configuration wins over every credential from the
+	// environment so that exactly one authentication mode is in effect
+	if !data.ManagementToken.IsNull() || !data.Authtoken.IsNull() || !data.Email.IsNull() || !data.OAuthClientID.IsNull() {
 		managementToken = data.ManagementToken.ValueString()
+		authtoken = data.Authtoken.ValueString()
+		email = data.Email.ValueString()
+		oauth.ClientID = data.OAuthClientID.ValueString()
 	}
 
-	if host == "" {
+	if !data.Password.IsNull() {
+		password = data.Password.ValueString()
+	}
+
+	if !data.TfaToken.IsNull() {
+		tfaToken = data.TfaToken.ValueString()
+	}
+
+	if !data.OAuthClientSecret.IsNull() {
+		oauth.ClientSecret = data.OAuthClientSecret.ValueString()
+	}
+
+	if !data.OAuthRefreshToken.IsNull() {
+		oauth.RefreshToken = data.OAuthRefreshToken.ValueString()
+	}
+
+	if !data.OAuthAppUID.IsNull() {
+		oauth.AppUID = data.OAuthAppUID.ValueString()
+	}
+
+	if !data.OAuthTokenEndpoint.IsNull() {
+		oauth.TokenEndpoint = data.OAuthTokenEndpoint.ValueString()
+	}
+
+	if host == "" && !resp.Diagnostics.HasError() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("host"),
 			"Missing ContentStack API Host",
 			"The provider cannot create the ContentStack API client as there is a missing or empty value for the ContentStack API host. "+
-				"Set the host value in the configuration or use the CONTENTSTACK_HOST environment variable. "+
+				"Set the region or host value in the configuration or use the CONTENTSTACK_REGION or CONTENTSTACK_HOST environment variable. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
-	if apiKey == "" {
+	authModes := 0
+	for _, credential := range []string{managementToken, authtoken, email, oauth.ClientID} {
+		if credential != "" {
+			authModes++
+		}
+	}
+
+	switch {
+	case authModes == 0:
 		resp.Diagnostics.AddAttributeError(
-			path.Root("api_key"),
-			"Missing ContentStack API Key",
-			"The provider cannot create the ContentStack API client as there is a missing or empty value for the ContentStack API Key. "+
-				"Set the API Key value in the configuration or use the CONTENTSTACK_API_KEY environment variable. "+
+			path.Root("management_token"),
+			"Missing ContentStack Credentials",
+			"The provider cannot create the ContentStack API client as there is no ContentStack Management Token, authtoken, user email or OAuth client ID. "+
+				"Set the management_token, authtoken, email or oauth_client_id values in the configuration or use the "+
+				"CONTENTSTACK_MANAGEMENT_TOKEN, CONTENTSTACK_AUTHTOKEN, CONTENTSTACK_EMAIL or CONTENTSTACK_OAUTH_CLIENT_ID environment variables. "+
+				"If any is already set, ensure the value is not empty.",
+		)
+	case authModes > 1:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("management_token"),
+			"Conflicting ContentStack Credentials",
+			"The provider cannot create the ContentStack API client as more than one of CONTENTSTACK_MANAGEMENT_TOKEN, CONTENTSTACK_AUTHTOKEN, CONTENTSTACK_EMAIL and CONTENTSTACK_OAUTH_CLIENT_ID are set. "+
+				"Set only one of them, or set the management_token, authtoken, email or oauth_client_id value in the configuration.",
+		)
+	}
+
+	if email != "" && password == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("password"),
+			"Missing ContentStack Password",
+			"The provider cannot log in to ContentStack as there is a missing or empty value for the password of the user given by email. "+
+				"Set the password value in the configuration or use the CONTENTSTACK_PASSWORD environment variable. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
 
-	if managementToken == "" {
+	if tfaToken != "" && email == "" {
 		resp.Diagnostics.AddAttributeError(
-			path.Root("management_token"),
-			"Missing ContentStack Management Token",
-			"The provider cannot create the ContentStack API client as there is a missing or empty value for the ContentStack Management Token. "+
-				"Set the Management Token value in the configuration or use the CONTENTSTACK_MANAGEMENT_TOKEN environment variable. "+
+			path.Root("tfa_token"),
+			"Unexpected ContentStack Two-Factor Token",
+			"The provider only uses a two-factor authentication token to log in with email and password. "+
+				"Set the email and password values, or remove the tfa_token value and the CONTENTSTACK_TFA_TOKEN environment variable.",
+		)
+	}
+
+	if oauth.ClientID != "" {
+		if oauth.ClientSecret == "" || oauth.RefreshToken == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("oauth_client_id"),
+				"Missing ContentStack OAuth Credentials",
+				"The provider cannot authenticate with the ContentStack OAuth app as the client secret or refresh token is missing or empty. "+
+					"Set the oauth_client_secret and oauth_refresh_token values in the configuration or use the "+
+					"CONTENTSTACK_OAUTH_CLIENT_SECRET and CONTENTSTACK_OAUTH_REFRESH_TOKEN environment variables. "+
+					"If either is already set, ensure the value is not empty.",
+			)
+		}
+		if oauth.TokenEndpoint == "" && region != "" {
+			oauth.TokenEndpoint, _ = oauthTokenEndpointForRegion(region)
+		}
+		if oauth.TokenEndpoint == "" && !resp.Diagnostics.HasError() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("oauth_token_endpoint"),
+				"Missing ContentStack OAuth Token Endpoint",
+				"The provider cannot authenticate with the ContentStack OAuth app as there is no token endpoint for a custom host. "+
+					"Set the region or oauth_token_endpoint value in the configuration or use the "+
+					"CONTENTSTACK_REGION or CONTENTSTACK_OAUTH_TOKEN_ENDPOINT environment variable.",
+			)
+		}
+	}
+
+	// user sessions are not scoped to a stack, so the api key is only
+	// required alongside a management token
+	if apiKey == "" && managementToken != "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("api_key"),
+			"Missing ContentStack API Key",
+			"The provider cannot create the ContentStack API client as there is a missing or empty value for the ContentStack API Key. "+
+				"Set the API Key value in the configuration or use the CONTENTSTACK_API_KEY environment variable. "+
 				"If either is already set, ensure the value is not empty.",
 		)
 	}
@@ -133,38 +449,269 @@ func (p *ContentStackProvider) Configure(ctx context.Context, req provider.Confi
 		return
 	}
 
-	// Create a new ContentStack API client using the configuration values
-	debugClient := false
-	if !data.Debug.IsUnknown() {
-		debugClient = data.Debug.ValueBool()
-	}
-	client, err := management.NewClient(&management.Configuration{
-		Host:      host,
-		Key:       apiKey,
-		Token:     managementToken,
-		Debug:     debugClient,
-		UserAgent: "terraform-provider-contentstacktypes",
-	})
+	requestsPerSecond := float64(defaultRequestsPerSecond)
+
+	if v := os.Getenv("CONTENTSTACK_REQUESTS_PER_SECOND"); v != "" {
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || n < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("requests_per_second"),
+				"Invalid ContentStack Requests Per Second",
+				fmt.Sprintf("The CONTENTSTACK_REQUESTS_PER_SECOND environment variable must be a non-negative number, got: %s", v),
+			)
+		}
+		requestsPerSecond = n
+	}
+
+	if !data.RequestsPerSecond.IsNull() {
+		requestsPerSecond = data.RequestsPerSecond.ValueFloat64()
+	}
+
+	requestTimeout := defaultRequestTimeout
+
+	requestTimeoutValue := os.Getenv("CONTENTSTACK_REQUEST_TIMEOUT")
+	if !data.RequestTimeout.IsNull() {
+		requestTimeoutValue = data.RequestTimeout.ValueString()
+	}
+
+	if requestTimeoutValue != "" {
+		d, err := time.ParseDuration(requestTimeoutValue)
+		if err != nil || d < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("request_timeout"),
+				"Invalid ContentStack Request Timeout",
+				fmt.Sprintf("The request_timeout value or CONTENTSTACK_REQUEST_TIMEOUT environment variable must be a non-negative duration such as \"30s\", got: %s", requestTimeoutValue),
+			)
+		}
+		requestTimeout = d
+	}
+
+	transportSettings := transportConfig{
+		ProxyURL:   os.Getenv("CONTENTSTACK_PROXY_URL"),
+		CACertFile: os.Getenv("CONTENTSTACK_CA_CERT_FILE"),
+		CACertPEM:  os.Getenv("CONTENTSTACK_CA_CERT_PEM"),
+		ClientCert: os.Getenv("CONTENTSTACK_CLIENT_CERT"),
+		ClientKey:  os.Getenv("CONTENTSTACK_CLIENT_KEY"),
+	}
+
+	if v := os.Getenv("CONTENTSTACK_INSECURE_SKIP_VERIFY"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("insecure_skip_verify"),
+				"Invalid ContentStack Insecure Skip Verify",
+				fmt.Sprintf("The CONTENTSTACK_INSECURE_SKIP_VERIFY environment variable must be true or false, got: %s", v),
+			)
+		}
+		transportSettings.InsecureSkipVerify = b
+	}
+
+	if !data.ProxyURL.IsNull() {
+		transportSettings.ProxyURL = data.ProxyURL.ValueString()
+	}
+
+	// a CA certificate in the configuration wins over either environment variable
+	if !data.CACertFile.IsNull() || !data.CACertPEM.IsNull() {
+		transportSettings.CACertFile = data.CACertFile.ValueString()
+		transportSettings.CACertPEM = data.CACertPEM.ValueString()
+	}
+
+	if !data.ClientCert.IsNull() {
+		transportSettings.ClientCert = data.ClientCert.ValueString()
+	}
+
+	if !data.ClientKey.IsNull() {
+		transportSettings.ClientKey = data.ClientKey.ValueString()
+	}
+
+	if !data.InsecureSkipVerify.IsNull() {
+		transportSettings.InsecureSkipVerify = data.InsecureSkipVerify.ValueBool()
+	}
+
+	transport, err := newTransport(transportSettings)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unable to Create ContentStack API Client",
-			"An unexpected error occurred when creating the ContentStack API client. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"ContentStack Client Error: "+err.Error(),
+			"Invalid ContentStack Transport Configuration",
+			"The provider cannot create the ContentStack API client as its proxy or TLS settings are invalid. "+
+				"Check the proxy_url, ca_cert_file, ca_cert_pem, client_cert and client_key values and their environment variables.\n\n"+
+				"Error: "+err.Error(),
 		)
+	}
+
+	logBodyMaxBytes := defaultLogBodyMaxBytes
+	if v := os.Getenv("CONTENTSTACK_LOG_BODY_MAX_BYTES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("log_body_max_bytes"),
+				"Invalid ContentStack Log Body Max Bytes",
+				fmt.Sprintf("The CONTENTSTACK_LOG_BODY_MAX_BYTES environment variable must be a non-negative whole number, got: %s", v),
+			)
+		}
+		logBodyMaxBytes = n
+	}
+
+	if !data.LogBodyMaxBytes.IsNull() {
+		logBodyMaxBytes = int(data.LogBodyMaxBytes.ValueInt64())
+	}
+
+	retries := retryPolicy{
+		MaxRetries: defaultMaxRetries,
+		MinWait:    retryMinWait,
+		MaxWait:    defaultRetryMaxWait,
+	}
+
+	if v := os.Getenv("CONTENTSTACK_MAX_RETRIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_retries"),
+				"Invalid ContentStack Max Retries",
+				fmt.Sprintf("The CONTENTSTACK_MAX_RETRIES environment variable must be a non-negative whole number, got: %s", v),
+			)
+		}
+		retries.MaxRetries = n
+	}
+
+	if !data.MaxRetries.IsNull() {
+		retries.MaxRetries = int(data.MaxRetries.ValueInt64())
+	}
+
+	retryMaxWait := os.Getenv("CONTENTSTACK_RETRY_MAX_WAIT")
+	if !data.RetryMaxWait.IsNull() {
+		retryMaxWait = data.RetryMaxWait.ValueString()
+	}
+
+	if retryMaxWait != "" {
+		d, err := time.ParseDuration(retryMaxWait)
+		if err != nil || d < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("retry_max_wait"),
+				"Invalid ContentStack Retry Max Wait",
+				fmt.Sprintf("The retry_max_wait value or CONTENTSTACK_RETRY_MAX_WAIT environment variable must be a non-negative duration such as \"30s\", got: %s", retryMaxWait),
+			)
+		}
+		retries.MaxWait = d
+	}
+
+	if retries.MaxWait < retries.MinWait {
+		retries.MinWait = retries.MaxWait
+	}
+
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Make the ContentStack API client available during DataSource and Resource
-	// type Configure methods.
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	// Create a new ContentStack API client using the configuration values
+	// requests are logged through tflog rather than the client's debug output,
+	// which prints credentials
+	var baseTransport http.RoundTripper = transport
+	if p.wrapTransport != nil {
+		baseTransport = p.wrapTransport(transport)
+	}
+	roundTripper := newLoggingTransport(baseTransport, logBodyMaxBytes)
+	// one limiter for every stack client
+	var limiter *rateLimiter
+	if requestsPerSecond > 0 {
+		limiter = newRateLimiter(requestsPerSecond)
+	}
+	newClient := func(key string) *apiClient {
+		c := newAPIClient(&management.Configuration{
+			Host:      host,
+			Key:       key,
+			Token:     managementToken,
+			UserAgent: "terraform-provider-contentstacktypes",
+		})
+		useTransport(c, roundTripper)
+		c.resty.SetTimeout(requestTimeout)
+		if limiter != nil {
+			useRateLimiter(c, limiter)
+		}
+		useRetries(c, retries)
+		return c
+	}
+	client := newClient(apiKey)
+
+	// authenticate applies the configured credentials to the client of each
+	// stack; a management token is already part of the client configuration
+	authenticate := func(c *apiClient) {}
+	switch {
+	case authtoken != "":
+		authenticate = func(c *apiClient) { useAuthtoken(c, authtoken) }
+	case email != "":
+		sessionToken, err := logIn(ctx, client, email, password, tfaToken)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Log In to ContentStack",
+				"An unexpected error occurred when logging in to ContentStack with the configured email and password. "+
+					"If the error is not clear, please contact the provider developers.\n\n"+
+					"ContentStack Client Error: "+err.Error(),
+			)
+			return
+		}
+		// every stack shares the one session, which is logged out through this client
+		authenticate = func(c *apiClient) { useAuthtoken(c, sessionToken) }
+		openSessions.add(client)
+	case oauth.ClientID != "":
+		source := newOAuthTokenSource(oauth)
+		source.httpClient = &http.Client{Transport: roundTripper, Timeout: requestTimeout}
+		// exchange the refresh token up front so that bad credentials are
+		// reported once here rather than on every resource
+		if _, err := source.Token(ctx); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Authenticate with ContentStack OAuth",
+				"An unexpected error occurred when exchanging the OAuth refresh token for an access token. "+
+					"If the error is not clear, please contact the provider developers.\n\n"+
+					"ContentStack Client Error: "+err.Error(),
+			)
+			return
+		}
+		authenticate = func(c *apiClient) { useOAuth(c, source) }
+	}
+	authenticate(client)
+
+	strictVersioning := false
+	if v := os.Getenv("CONTENTSTACK_STRICT_VERSIONING"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("strict_versioning"),
+				"Invalid ContentStack Strict Versioning",
+				fmt.Sprintf("The CONTENTSTACK_STRICT_VERSIONING environment variable must be true or false, got: %s", v),
+			)
+			return
+		}
+		strictVersioning = b
+	}
+
+	if !data.StrictVersioning.IsNull() {
+		strictVersioning = data.StrictVersioning.ValueBool()
+	}
+
+	clients := newClientFactory(apiKey, func(key string) (*apiClient, error) {
+		c := newClient(key)
+		authenticate(c)
+		return c, nil
+	})
+	if apiKey != "" {
+		clients.clients[apiKey] = client
+	}
+	clients.stackScoped = managementToken != ""
+	clients.strictVersioning = strictVersioning
+
+	// Make the ContentStack API client factory available during DataSource and
+	// Resource type Configure methods.
+	resp.DataSourceData = clients
+	resp.ResourceData = clients
 }
 
 func (p *ContentStackProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
+		NewContentTypeResource,
 		NewEnvironmentResource,
 		NewGlobalFieldResource,
+		NewStackResource,
+		NewStackShareResource,
 	}
 }
 
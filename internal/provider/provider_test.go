@@ -1,15 +1,328 @@
 package provider
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/davidalpert/terraform-provider-contentstack/internal/contentstacktest"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
 // acceptance testing. The factory function will be invoked for every Terraform
 // CLI command executed to create a provider server to which the CLI can
 // reattach.
-//var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-//	"contentstack": providerserver.NewProtocol6WithError(New("test")()),
-//}
-
-//func testAccPreCheck(t *testing.T) {
-//	// You can add code here to run prior to any test case execution, for example assertions
-//	// about the appropriate environment variables being set are common to see in a pre-check
-//	// function.
-//}
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"contentstack": providerserver.NewProtocol6WithError(New("test", "none", "unknown")()),
+}
+
+// testAccFakeAPI starts a fake Contentstack API which is closed when the test
+// ends, so that acceptance tests need neither credentials nor network access.
+func testAccFakeAPI(t *testing.T) *contentstacktest.Server {
+	t.Helper()
+
+	s := contentstacktest.NewServer()
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// testAccProviderConfig configures the provider to talk to the fake API, with
+// any further provider settings given.
+func testAccProviderConfig(s *contentstacktest.Server, settings ...string) string {
+	return fmt.Sprintf(`
+provider "contentstack" {
+  host                = %q
+  api_key             = %q
+  management_token    = %q
+  requests_per_second = 0
+  max_retries         = 0
+%s
+}
+`, s.URL, s.APIKey, s.ManagementToken, strings.Join(settings, "\n"))
+}
+
+// testAccPlanCheckFunc is a plan check which runs f, to change the API behind
+// Terraform's back between the commands of a test step. As a PreApply check
+// it runs between the plan and the apply of that plan.
+type testAccPlanCheckFunc func() error
+
+func (f testAccPlanCheckFunc) CheckPlan(ctx context.Context, req plancheck.CheckPlanRequest, resp *plancheck.CheckPlanResponse) {
+	resp.Error = f()
+}
+
+// testAccRemoveBeforeDestroy deletes a record behind Terraform's back. As a
+// PostApplyPostRefresh check of the last step it runs after the last refresh,
+// so the destroy which ends the test, and which does not refresh, deletes a
+// record which is already gone.
+func testAccRemoveBeforeDestroy(api *contentstacktest.Server, c contentstacktest.Collection, id string) plancheck.PlanCheck {
+	return testAccPlanCheckFunc(func() error {
+		if !api.Remove(c, id) {
+			return fmt.Errorf("expected %s %#v to exist", c.Title, id)
+		}
+		return nil
+	})
+}
+
+// testAccCassetteProviderFactories instantiates a provider whose API traffic
+// goes through the cassette of the test, testdata/cassettes/<test name>.json,
+// in the mode which contentstacktest.CassetteMode() selects: replaying it by
+// default, or recording it from a real stack; secrets are the credentials to
+// scrub from the recording.
+func testAccCassetteProviderFactories(t *testing.T, secrets ...string) map[string]func() (tfprotov6.ProviderServer, error) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "cassettes", t.Name()+".json")
+	return testAccCassetteProviderFactoriesWithMode(t, contentstacktest.CassetteMode(), path, secrets...)
+}
+
+// testAccCassetteProviderFactoriesWithMode instantiates a provider whose API
+// traffic goes through a cassette at path. In record mode the provider talks
+// to the API its configuration points at and the sanitized traffic is saved
+// when the test ends, with secrets scrubbed; in replay mode the cassette
+// answers every request without network access, the test is skipped when
+// there is no cassette yet, and fails when the recorded requests are no
+// longer sent.
+func testAccCassetteProviderFactoriesWithMode(t *testing.T, mode, path string, secrets ...string) map[string]func() (tfprotov6.ProviderServer, error) {
+	t.Helper()
+
+	var wrap func(http.RoundTripper) http.RoundTripper
+	switch mode {
+	case contentstacktest.CassetteModeRecord:
+		recorder := contentstacktest.NewRecorder(secrets...)
+		wrap = recorder.Wrap
+		t.Cleanup(func() {
+			if err := recorder.Cassette().Save(path); err != nil {
+				t.Errorf("saving cassette: %s", err)
+			}
+		})
+	default:
+		cassette, err := contentstacktest.LoadCassette(path)
+		if errors.Is(err, os.ErrNotExist) {
+			t.Skipf("no cassette at %s; record it with %s=%s", path, contentstacktest.CassetteModeEnvVar, contentstacktest.CassetteModeRecord)
+		}
+		if err != nil {
+			t.Fatalf("loading cassette: %s", err)
+		}
+		replayer := contentstacktest.NewReplayer(cassette)
+		wrap = func(http.RoundTripper) http.RoundTripper { return replayer }
+		t.Cleanup(func() {
+			if unused := replayer.Unused(); len(unused) > 0 && !t.Failed() {
+				t.Errorf("%d interactions of cassette %s were not replayed, starting with %s %s; record it again with %s=%s",
+					len(unused), path, unused[0].Request.Method, unused[0].Request.URL, contentstacktest.CassetteModeEnvVar, contentstacktest.CassetteModeRecord)
+			}
+		})
+	}
+
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"contentstack": providerserver.NewProtocol6WithError(&ContentStackProvider{
+			version:       "test",
+			commit:        "none",
+			date:          "unknown",
+			wrapTransport: wrap,
+		}),
+	}
+}
+
+// testAccCassetteProviderConfig configures the provider of a test which uses
+// testAccCassetteProviderFactories: from the CONTENTSTACK_* environment
+// variables of the stack when recording, and with placeholder credentials
+// when replaying, which sends no request.
+func testAccCassetteProviderConfig() string {
+	if contentstacktest.CassetteMode() == contentstacktest.CassetteModeRecord {
+		return `
+provider "contentstack" {}
+`
+	}
+
+	return fmt.Sprintf(`
+provider "contentstack" {
+  host                = "https://api.contentstack.io"
+  api_key             = %q
+  management_token    = %q
+  requests_per_second = 0
+  max_retries         = 0
+}
+`, contentstacktest.Redacted, contentstacktest.Redacted)
+}
+
+// testAccCassetteSecrets are the credentials of the stack a cassette is
+// recorded from.
+func testAccCassetteSecrets() []string {
+	return []string{
+		os.Getenv("CONTENTSTACK_API_KEY"),
+		os.Getenv("CONTENTSTACK_MANAGEMENT_TOKEN"),
+		os.Getenv("CONTENTSTACK_AUTHTOKEN"),
+	}
+}
+
+// TestAccCassette checks that a recording replays, against the fake API.
+func TestAccCassette(t *testing.T) {
+	api := testAccFakeAPI(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	steps := []resource.TestStep{
+		{
+			Config: testAccEnvironmentResourceConfig(api, `
+  name = "staging"
+  urls = {
+    en-us = "https://staging.example.com"
+  }
+`),
+			Check: resource.TestCheckResourceAttr("contentstack_environment.test", "version", "1"),
+		},
+		{
+			Config: testAccEnvironmentResourceConfig(api, `
+  name           = "staging"
+  deploy_content = true
+`),
+			Check: resource.TestCheckResourceAttr("contentstack_environment.test", "version", "2"),
+		},
+	}
+
+	t.Run("record", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			ProtoV6ProviderFactories: testAccCassetteProviderFactoriesWithMode(t, contentstacktest.CassetteModeRecord, path, api.APIKey, api.ManagementToken),
+			CheckDestroy:             testAccCheckEnvironmentDestroyed(api, "staging"),
+			Steps:                    steps,
+		})
+	})
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Skipf("nothing recorded: %s", err)
+	}
+	if strings.Contains(string(b), api.APIKey) || strings.Contains(string(b), api.ManagementToken) {
+		t.Errorf("expected the credentials to be scrubbed from the cassette, got %s", b)
+	}
+
+	// the replay needs neither the API nor its credentials
+	api.Close()
+
+	t.Run("replay", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			ProtoV6ProviderFactories: testAccCassetteProviderFactoriesWithMode(t, contentstacktest.CassetteModeReplay, path),
+			Steps:                    steps,
+		})
+	})
+}
+
+// testUnsetProviderEnv clears the environment variables which configure the
+// provider, so that a test only sees the settings it makes.
+func testUnsetProviderEnv(t *testing.T) {
+	t.Helper()
+
+	for _, name := range os.Environ() {
+		if name, _, _ := strings.Cut(name, "="); strings.HasPrefix(name, "CONTENTSTACK_") {
+			t.Setenv(name, "")
+		}
+	}
+}
+
+// testProviderConfigureCase is a provider configuration which Configure
+// rejects before making any request.
+type testProviderConfigureCase struct {
+	env      map[string]string
+	settings string
+	expected *regexp.Regexp
+}
+
+func testProviderConfigureErrors(t *testing.T, tests map[string]testProviderConfigureCase) {
+	t.Helper()
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			testUnsetProviderEnv(t)
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+
+			resource.UnitTest(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: fmt.Sprintf(`
+provider "contentstack" {
+%s
+}
+
+data "contentstack_environment" "test" {
+  name = "staging"
+}
+`, test.settings),
+						ExpectError: test.expected,
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestProviderConfigureRegion(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	testProviderConfigureErrors(t, map[string]testProviderConfigureCase{
+		"host and region from the environment": {
+			env:      map[string]string{"CONTENTSTACK_HOST": api.URL, "CONTENTSTACK_REGION": "eu"},
+			settings: fmt.Sprintf("api_key = %q\nmanagement_token = %q", api.APIKey, api.ManagementToken),
+			expected: regexp.MustCompile(`Conflicting ContentStack API Host and Region`),
+		},
+		"host and region in the configuration": {
+			settings: fmt.Sprintf("host = %q\nregion = \"eu\"\napi_key = %q\nmanagement_token = %q", api.URL, api.APIKey, api.ManagementToken),
+			expected: regexp.MustCompile(`Invalid Attribute Combination`),
+		},
+		"unknown region from the environment": {
+			env:      map[string]string{"CONTENTSTACK_REGION": "mars"},
+			settings: fmt.Sprintf("api_key = %q\nmanagement_token = %q", api.APIKey, api.ManagementToken),
+			expected: regexp.MustCompile(`Unknown ContentStack Region`),
+		},
+		"unknown region in the configuration": {
+			settings: fmt.Sprintf("region = \"mars\"\napi_key = %q\nmanagement_token = %q", api.APIKey, api.ManagementToken),
+			expected: regexp.MustCompile(`Invalid Attribute Value Match`),
+		},
+	})
+}
+
+func TestProviderConfigureCredentials(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	testProviderConfigureErrors(t, map[string]testProviderConfigureCase{
+		"no credentials": {
+			settings: fmt.Sprintf("host = %q\napi_key = %q", api.URL, api.APIKey),
+			expected: regexp.MustCompile(`Missing ContentStack Credentials`),
+		},
+		"more than one kind of credentials from the environment": {
+			env:      map[string]string{"CONTENTSTACK_MANAGEMENT_TOKEN": api.ManagementToken, "CONTENTSTACK_AUTHTOKEN": api.Authtoken},
+			settings: fmt.Sprintf("host = %q\napi_key = %q", api.URL, api.APIKey),
+			expected: regexp.MustCompile(`Conflicting ContentStack Credentials`),
+		},
+		"more than one kind of credentials in the configuration": {
+			settings: fmt.Sprintf("host = %q\napi_key = %q\nmanagement_token = %q\nauthtoken = %q", api.URL, api.APIKey, api.ManagementToken, api.Authtoken),
+			expected: regexp.MustCompile(`Invalid Attribute Combination`),
+		},
+		"email without password": {
+			env:      map[string]string{"CONTENTSTACK_EMAIL": "dev@example.com"},
+			settings: fmt.Sprintf("host = %q", api.URL),
+			expected: regexp.MustCompile(`Missing ContentStack Password`),
+		},
+		"tfa_token without email": {
+			settings: fmt.Sprintf("host = %q\nauthtoken = %q\ntfa_token = \"123456\"", api.URL, api.Authtoken),
+			expected: regexp.MustCompile(`Unexpected ContentStack Two-Factor Token`),
+		},
+		"management_token without api_key": {
+			settings: fmt.Sprintf("host = %q\nmanagement_token = %q", api.URL, api.ManagementToken),
+			expected: regexp.MustCompile(`Missing ContentStack API Key`),
+		},
+	})
+}
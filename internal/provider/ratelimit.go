@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"github.com/go-resty/resty/v2"
+	"math"
+	"sync"
+	"time"
+)
+
+// defaultRequestsPerSecond matches the per-organization limit which
+// Contentstack publishes for the Content Management API.
+const defaultRequestsPerSecond = 10
+
+// rateLimiter is a token bucket shared by the clients of every stack, as
+// Terraform runs resource operations in parallel and Contentstack counts
+// requests across the whole organization.
+type rateLimiter struct {
+	rate  float64 // tokens added per second
+	burst float64
+	now   func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	burst := math.Max(1, math.Floor(requestsPerSecond))
+	return &rateLimiter{
+		rate:   requestsPerSecond,
+		burst:  burst,
+		now:    time.Now,
+		tokens: burst,
+	}
+}
+
+// reserve takes a token and returns how long to wait before it may be used;
+// tokens may go negative so that waiting callers are served in order.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns a token which was reserved but not used.
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = math.Min(l.burst, l.tokens+1)
+}
+
+// Wait blocks until a request may be sent or the context is done.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	wait := l.reserve()
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	}
+}
+
+// useRateLimiter makes every request of the client, including retries, wait
+// for the limiter first.
+func useRateLimiter(c *apiClient, l *rateLimiter) {
+	c.resty.OnBeforeRequest(func(_ *resty.Client, r *resty.Request) error {
+		return l.Wait(r.Context())
+	})
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	l := newRateLimiter(2)
+	l.now = func() time.Time { return now }
+
+	// the bucket starts full
+	for i := 0; i < 2; i++ {
+		if wait := l.reserve(); wait != 0 {
+			t.Fatalf("expected request %d to go straight away, waited %s", i, wait)
+		}
+	}
+
+	// then callers queue up behind each other
+	if wait := l.reserve(); wait != 500*time.Millisecond {
+		t.Errorf("expected to wait 500ms, got %s", wait)
+	}
+	if wait := l.reserve(); wait != time.Second {
+		t.Errorf("expected to wait 1s, got %s", wait)
+	}
+
+	// and the bucket refills over time, up to its burst size
+	now = now.Add(10 * time.Second)
+	if wait := l.reserve(); wait != 0 {
+		t.Errorf("expected no wait after refilling, got %s", wait)
+	}
+	if l.tokens != 1 {
+		t.Errorf("expected bucket to refill to 2 tokens, got %v left after a request", l.tokens)
+	}
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	t.Parallel()
+
+	l := newRateLimiter(0.01)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx); err == nil {
+		t.Fatal("expected error, got no error")
+	}
+	if l.tokens < -0.5 {
+		t.Errorf("expected the cancelled reservation to be returned, got %v tokens", l.tokens)
+	}
+}
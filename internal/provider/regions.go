@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"sort"
+	"strings"
+)
+
+// managementHostsByRegion maps each Contentstack region to the base URL of its
+// Content Management API.
+var managementHostsByRegion = map[string]string{
+	"na":       "https://api.contentstack.io",
+	"eu":       "https://eu-api.contentstack.com",
+	"azure-na": "https://azure-na-api.contentstack.com",
+	"azure-eu": "https://azure-eu-api.contentstack.com",
+	"gcp-na":   "https://gcp-na-api.contentstack.com",
+}
+
+// appHostsByRegion maps each Contentstack region to the base URL of its web
+// app, which also serves the OAuth token endpoint.
+var appHostsByRegion = map[string]string{
+	"na":       "https://app.contentstack.com",
+	"eu":       "https://eu-app.contentstack.com",
+	"azure-na": "https://azure-na-app.contentstack.com",
+	"azure-eu": "https://azure-eu-app.contentstack.com",
+	"gcp-na":   "https://gcp-na-app.contentstack.com",
+}
+
+// regionNames returns the supported region names in a stable order.
+func regionNames() []string {
+	names := make([]string, 0, len(managementHostsByRegion))
+	for r := range managementHostsByRegion {
+		names = append(names, r)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// hostForRegion returns the management API host for a region name.
+func hostForRegion(region string) (string, bool) {
+	host, ok := managementHostsByRegion[strings.ToLower(strings.TrimSpace(region))]
+	return host, ok
+}
+
+// oauthTokenEndpointForRegion returns the OAuth token endpoint for a region name.
+func oauthTokenEndpointForRegion(region string) (string, bool) {
+	host, ok := appHostsByRegion[strings.ToLower(strings.TrimSpace(region))]
+	if !ok {
+		return "", false
+	}
+	return host + "/apps-api/apps/token", true
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestHostForRegion(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		region       string
+		expectedHost string
+		expectedOK   bool
+	}
+	tests := map[string]testCase{
+		"na": {
+			region:       "na",
+			expectedHost: "https://api.contentstack.io",
+			expectedOK:   true,
+		},
+		"mixed case with whitespace": {
+			region:       " Azure-EU ",
+			expectedHost: "https://azure-eu-api.contentstack.com",
+			expectedOK:   true,
+		},
+		"unknown": {
+			region: "mars",
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			host, ok := hostForRegion(test.region)
+
+			if ok != test.expectedOK {
+				t.Fatalf("expected ok to be %t, got %t", test.expectedOK, ok)
+			}
+
+			if host != test.expectedHost {
+				t.Errorf("expected %q, got %q", test.expectedHost, host)
+			}
+		})
+	}
+}
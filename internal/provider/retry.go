@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"github.com/go-resty/resty/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxRetries   = 3
+	defaultRetryMaxWait = 30 * time.Second
+	retryMinWait        = time.Second
+)
+
+// retryPolicy controls how often and for how long a request is retried.
+type retryPolicy struct {
+	MaxRetries int
+	MinWait    time.Duration
+	MaxWait    time.Duration
+}
+
+// useRetries retries requests which Contentstack rate limited, and idempotent
+// requests which failed with a transient server or network error, backing off
+// exponentially or for as long as a Retry-After header asks.
+func useRetries(c *apiClient, policy retryPolicy) {
+	c.resty.
+		SetRetryCount(policy.MaxRetries).
+		SetRetryWaitTime(policy.MinWait).
+		SetRetryMaxWaitTime(policy.MaxWait).
+		SetRetryAfter(retryAfter).
+		AddRetryCondition(isRetryable)
+}
+
+// isRetryable reports whether a request may succeed when sent again and is
+// safe to send again.
+//
+// A rate limited request was not processed, so it is retried whatever its
+// method. A server or network error may come after the API applied the
+// request, so only idempotent requests are retried then, lest a POST create
+// something twice. A request which was cancelled or ran out of time, or which
+// was never sent because a hook such as the rate limiter or the OAuth token
+// refresh failed, is not retried.
+func isRetryable(r *resty.Response, err error) bool {
+	if r == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if r.StatusCode() == http.StatusTooManyRequests {
+		return true
+	}
+	if r.Request == nil || !isIdempotent(r.Request.Method) {
+		return false
+	}
+	return err != nil || r.StatusCode() >= http.StatusInternalServerError
+}
+
+// isIdempotent reports whether sending a request with the method many times
+// has the same effect as sending it once.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter honours a Retry-After header; a zero result falls back to
+// exponential backoff.
+func retryAfter(_ *resty.Client, r *resty.Response) (time.Duration, error) {
+	if r == nil {
+		return 0, nil
+	}
+	return parseRetryAfter(r.Header().Get("Retry-After"), time.Now()), nil
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+
+	return 0
+}
@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davidalpert/go-contentstack/v1/management"
+	"github.com/go-resty/resty/v2"
+)
+
+func newRetryingTestClient(t *testing.T, url string, policy retryPolicy) *apiClient {
+	t.Helper()
+
+	client := newAPIClient(&management.Configuration{Host: url, Key: "api-key", Token: "management-token"})
+	useRetries(client, policy)
+
+	return client
+}
+
+func TestRetriesRateLimitedAndServerErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, `{"error_message":"Too many requests"}`, http.StatusTooManyRequests)
+		case 2:
+			http.Error(w, `{"error_message":"Bad gateway"}`, http.StatusBadGateway)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"locales":[{"code":"en-us"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := newRetryingTestClient(t, server.URL, retryPolicy{MaxRetries: 3, MinWait: time.Millisecond, MaxWait: 20 * time.Millisecond})
+
+	locales, err := getLocales(context.Background(), client)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	if len(locales) != 1 {
+		t.Errorf("expected 1 locale, got %d", len(locales))
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetriesGiveUpAfterMaxRetries(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, `{"error_message":"Too many requests"}`, http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := newRetryingTestClient(t, server.URL, retryPolicy{MaxRetries: 2, MinWait: time.Millisecond, MaxWait: time.Millisecond})
+
+	if _, err := getLocales(context.Background(), client); err == nil {
+		t.Fatal("expected error, got no error")
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetriesSkipClientErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, `{"error_message":"Not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := newRetryingTestClient(t, server.URL, retryPolicy{MaxRetries: 3, MinWait: time.Millisecond, MaxWait: time.Millisecond})
+
+	if _, err := getLocales(context.Background(), client); err == nil {
+		t.Fatal("expected error, got no error")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetriesSkipFailedHooks(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	client := newRetryingTestClient(t, server.URL, retryPolicy{MaxRetries: 3, MinWait: time.Millisecond, MaxWait: time.Millisecond})
+	var hooks int32
+	client.resty.OnBeforeRequest(func(_ *resty.Client, _ *resty.Request) error {
+		atomic.AddInt32(&hooks, 1)
+		return errors.New("refreshing OAuth access token: invalid_grant")
+	})
+
+	if _, err := getLocales(context.Background(), client); err == nil {
+		t.Fatal("expected error, got no error")
+	}
+	if hooks != 1 || calls != 0 {
+		t.Errorf("expected the hook to fail once and no calls, got %d hooks and %d calls", hooks, calls)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	response := func(method string, status int) *resty.Response {
+		return &resty.Response{Request: &resty.Request{Method: method}, RawResponse: &http.Response{StatusCode: status}}
+	}
+	networkError := func(method string) *resty.Response {
+		return &resty.Response{Request: &resty.Request{Method: method}}
+	}
+
+	type testCase struct {
+		response *resty.Response
+		err      error
+		expected bool
+	}
+	tests := map[string]testCase{
+		"rate limited GET":      {response: response(http.MethodGet, http.StatusTooManyRequests), expected: true},
+		"rate limited POST":     {response: response(http.MethodPost, http.StatusTooManyRequests), expected: true},
+		"server error GET":      {response: response(http.MethodGet, http.StatusBadGateway), expected: true},
+		"server error PUT":      {response: response(http.MethodPut, http.StatusServiceUnavailable), expected: true},
+		"server error DELETE":   {response: response(http.MethodDelete, http.StatusInternalServerError), expected: true},
+		"server error POST":     {response: response(http.MethodPost, http.StatusBadGateway), expected: false},
+		"client error GET":      {response: response(http.MethodGet, http.StatusUnprocessableEntity), expected: false},
+		"success":               {response: response(http.MethodGet, http.StatusOK), expected: false},
+		"network error GET":     {response: networkError(http.MethodGet), err: errors.New("connection reset by peer"), expected: true},
+		"network error POST":    {response: networkError(http.MethodPost), err: errors.New("connection reset by peer"), expected: false},
+		"cancelled":             {response: networkError(http.MethodGet), err: context.Canceled, expected: false},
+		"deadline exceeded":     {response: networkError(http.MethodGet), err: context.DeadlineExceeded, expected: false},
+		"hook failed, not sent": {err: context.Canceled, expected: false},
+		"rate limiter failed":   {err: errors.New("rate: Wait(n=1) would exceed context deadline"), expected: false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isRetryable(test.response, test.err); got != test.expected {
+				t.Errorf("expected %t, got %t", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		value    string
+		expected time.Duration
+	}
+	tests := map[string]testCase{
+		"empty":     {value: "", expected: 0},
+		"seconds":   {value: "3", expected: 3 * time.Second},
+		"http date": {value: "Thu, 01 Jun 2023 12:00:05 GMT", expected: 5 * time.Second},
+		"past date": {value: "Thu, 01 Jun 2023 11:59:00 GMT", expected: 0},
+		"garbage":   {value: "soon", expected: 0},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := parseRetryAfter(test.value, now); got != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, got)
+			}
+		})
+	}
+}
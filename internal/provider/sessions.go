@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"sync"
+)
+
+// userSessions tracks the user sessions which the provider opened by logging
+// in with an email and password, so that they can be closed on shutdown.
+type userSessions struct {
+	mu      sync.Mutex
+	clients []*apiClient
+}
+
+var openSessions = &userSessions{}
+
+func (s *userSessions) add(c *apiClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clients = append(s.clients, c)
+}
+
+// CloseSessions logs out of every user session opened by the provider; it is
+// called once the provider server has stopped serving requests.
+//
+// Sessions for an authtoken supplied by the user are left open as they are
+// not the provider's to close.
+func CloseSessions(ctx context.Context) {
+	openSessions.mu.Lock()
+	defer openSessions.mu.Unlock()
+
+	for _, c := range openSessions.clients {
+		if err := logOut(ctx, c); err != nil {
+			tflog.Warn(ctx, "unable to log out of ContentStack user session", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+	openSessions.clients = nil
+}
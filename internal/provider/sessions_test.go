@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davidalpert/go-contentstack/v1/management"
+)
+
+func TestUserSessionLogInAndClose(t *testing.T) {
+	var loggedOutWith string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/user-session" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			var body userSessionRequestBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if body.User.Email != "user@example.com" || body.User.Password != "secret" {
+				http.Error(w, `{"error_message":"Looks like your email or password is invalid."}`, http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"user":{"authtoken":"session-token"}}`))
+		case http.MethodDelete:
+			loggedOutWith = r.Header.Get("authtoken")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"notice":"You've logged out successfully."}`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := newAPIClient(&management.Configuration{Host: server.URL})
+
+	if _, err := logIn(ctx, client, "user@example.com", "wrong", ""); err == nil {
+		t.Fatal("expected error, got no error")
+	}
+
+	authtoken, err := logIn(ctx, client, "user@example.com", "secret", "")
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	if authtoken != "session-token" {
+		t.Fatalf("expected authtoken %q, got %q", "session-token", authtoken)
+	}
+
+	useAuthtoken(client, authtoken)
+	if got := client.resty.Header.Get("Authorization"); got != "" {
+		t.Errorf("expected no Authorization header, got %q", got)
+	}
+
+	openSessions.add(client)
+	CloseSessions(ctx)
+
+	if loggedOutWith != "session-token" {
+		t.Errorf("expected session to be logged out with %q, got %q", "session-token", loggedOutWith)
+	}
+	if len(openSessions.clients) != 0 {
+		t.Errorf("expected no open sessions, got %d", len(openSessions.clients))
+	}
+}
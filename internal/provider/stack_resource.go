@@ -0,0 +1,317 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	mystringplanmodifiers "github.com/davidalpert/terraform-provider-contentstack/internal/tfutils/stringplanmodifiers"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &StackResource{}
+var _ resource.ResourceWithImportState = &StackResource{}
+
+func NewStackResource() resource.Resource {
+	return &StackResource{}
+}
+
+// StackResource defines the resource implementation.
+type StackResource struct {
+	clients *clientFactory
+}
+
+// StackResourceModel describes the resource data model.
+type StackResourceModel struct {
+	APIKey          types.String   `tfsdk:"api_key"`
+	Description     types.String   `tfsdk:"description"`
+	ID              types.String   `tfsdk:"id"`
+	MasterLocale    types.String   `tfsdk:"master_locale"`
+	Name            types.String   `tfsdk:"name"`
+	OrganizationUID types.String   `tfsdk:"organization_uid"`
+	UID             types.String   `tfsdk:"uid"`
+	Timeouts        timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (data *StackResourceModel) Update(s *stackJSON) {
+	data.APIKey = types.StringValue(s.APIKey)
+	data.Description = types.StringValue(s.Description)
+	data.ID = types.StringValue(s.APIKey)
+	data.MasterLocale = types.StringValue(s.MasterLocale)
+	data.Name = types.StringValue(s.Name)
+	data.OrganizationUID = types.StringValue(s.OrgUID)
+	data.UID = types.StringValue(s.UID)
+}
+
+func (data *StackResourceModel) Export() *stackJSON {
+	return &stackJSON{
+		APIKey:       data.APIKey.ValueString(),
+		Description:  data.Description.ValueString(),
+		MasterLocale: data.MasterLocale.ValueString(),
+		Name:         data.Name.ValueString(),
+	}
+}
+
+// stackErrorPath maps the properties of a Stack request to the attributes
+// which set them.
+var stackErrorPath = errorPathToAttributes("description", "master_locale", "name")
+
+func (r *StackResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stack"
+}
+
+func (r *StackResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Stack resource; creating a stack needs user credentials (`authtoken`, `email` or `oauth_client_id`) as management tokens are scoped to an existing stack.",
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+
+		Attributes: map[string]schema.Attribute{
+			"api_key": schema.StringAttribute{
+				MarkdownDescription: "API Key of the Stack, for use as the `stack_api_key` of the resources in it",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "description of the Stack",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					mystringplanmodifiers.DefaultValue(""),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "internal terraform resource id (matches the api_key when the Stack has been created/imported)",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"master_locale": schema.StringAttribute{
+				MarkdownDescription: "code of the master locale of the Stack; cannot be changed once the Stack is created",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					mystringplanmodifiers.DefaultValue("en-us"),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "name of the Stack",
+				Required:            true,
+			},
+			"organization_uid": schema.StringAttribute{
+				MarkdownDescription: "uid of the Organization which owns the Stack",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"uid": schema.StringAttribute{
+				MarkdownDescription: "internal contentstack identifier",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *StackResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	clients, ok := req.ProviderData.(*clientFactory)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *clientFactory, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.clients = clients
+}
+
+func (r *StackResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *StackResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, dg := data.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// the stack has no api key until it is created
+	client, err := r.clients.Unscoped()
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to create Stack %#v", data.Name.ValueString()), err, nil)
+		return
+	}
+
+	created, err := createStackJSON(ctx, client, data.OrganizationUID.ValueString(), data.Export())
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to create Stack %#v", data.Name.ValueString()), err, stackErrorPath)
+		return
+	}
+
+	data.Update(created)
+
+	tflog.Trace(ctx, "created a Stack", map[string]interface{}{
+		"uid":  created.UID,
+		"name": created.Name,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StackResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *StackResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, dg := data.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// never fall back to the provider api_key here, which is another stack
+	client, err := r.clients.Client(data.ID.ValueString())
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to read Stack %#v", data.Name.ValueString()), err, nil)
+		return
+	}
+
+	s, err := getStackJSON(ctx, client, false)
+	if isStackNotFound(err) {
+		// deleted outside Terraform; planning to create it again
+		tflog.Warn(ctx, "Stack not found, removing it from state", map[string]interface{}{
+			"name": data.Name.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to read Stack %#v", data.Name.ValueString()), err, nil)
+		return
+	}
+
+	data.Update(s)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StackResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *StackResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, dg := data.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := r.clients.Client(data.ID.ValueString())
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to update Stack %#v", data.Name.ValueString()), err, nil)
+		return
+	}
+
+	// the name and description are the only properties which can change, and
+	// they are saved as planned rather than as echoed by the response, which
+	// may leave out what did not change
+	_, err = updateStackJSON(ctx, client, data.Export())
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to update Stack %#v", data.Name.ValueString()), err, stackErrorPath)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StackResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *StackResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, dg := data.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := r.clients.Client(data.ID.ValueString())
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to delete Stack %#v", data.Name.ValueString()), err, nil)
+		return
+	}
+
+	err = deleteStack(ctx, client)
+	if isStackNotFound(err) {
+		// already deleted outside Terraform
+		return
+	}
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to delete Stack %#v", data.Name.ValueString()), err, nil)
+		return
+	}
+}
+
+func (r *StackResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/davidalpert/terraform-provider-contentstack/internal/contentstacktest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccStackResourceConfig configures the provider with the authtoken of
+// the fake's user, as creating a stack needs user credentials.
+func testAccStackResourceConfig(api *contentstacktest.Server, body string) string {
+	return fmt.Sprintf(`
+provider "contentstack" {
+  host                = %q
+  authtoken           = %q
+  requests_per_second = 0
+  max_retries         = 0
+}
+
+resource "contentstack_stack" "test" {
+  organization_uid = %q
+%s
+}
+`, api.URL, api.Authtoken, api.OrganizationUID, body)
+}
+
+// testAccCheckStacksDestroyed checks that destroying the configuration
+// deleted every stack but the fake's own.
+func testAccCheckStacksDestroyed(api *contentstacktest.Server) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "contentstack_stack" {
+				continue
+			}
+			if _, ok := api.Get(contentstacktest.Stacks, rs.Primary.ID); ok {
+				return fmt.Errorf("expected Stack %#v to be deleted", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccStackResource(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	var apiKey string
+	config := func(description string) string {
+		return testAccStackResourceConfig(api, fmt.Sprintf(`
+  name        = "Blog"
+  description = %q
+`, description))
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckStacksDestroyed(api),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: config("created by terraform"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_stack.test", "name", "Blog"),
+					resource.TestCheckResourceAttr("contentstack_stack.test", "master_locale", "en-us"),
+					resource.TestCheckResourceAttr("contentstack_stack.test", "organization_uid", api.OrganizationUID),
+					resource.TestCheckResourceAttrPair("contentstack_stack.test", "id", "contentstack_stack.test", "api_key"),
+					func(s *terraform.State) error {
+						apiKey = s.RootModule().Resources["contentstack_stack.test"].Primary.ID
+						if _, ok := api.Get(contentstacktest.Stacks, apiKey); !ok {
+							return fmt.Errorf("expected Stack %#v to exist", apiKey)
+						}
+						return nil
+					},
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "contentstack_stack.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+			// Update and Read testing
+			{
+				Config: config("updated by terraform"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_stack.test", plancheck.ResourceActionUpdate),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_stack.test", "description", "updated by terraform"),
+					func(s *terraform.State) error {
+						r, _ := api.Get(contentstacktest.Stacks, apiKey)
+						if r["description"] != "updated by terraform" {
+							return fmt.Errorf("expected the description to be updated, got %v", r["description"])
+						}
+						return nil
+					},
+				),
+			},
+			// changing only the name keeps the description
+			{
+				Config: testAccStackResourceConfig(api, `
+  name        = "Blog Posts"
+  description = "updated by terraform"
+`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_stack.test", plancheck.ResourceActionUpdate),
+					},
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("contentstack_stack.test", "name", "Blog Posts"),
+					resource.TestCheckResourceAttr("contentstack_stack.test", "description", "updated by terraform"),
+					func(s *terraform.State) error {
+						r, _ := api.Get(contentstacktest.Stacks, apiKey)
+						if r["name"] != "Blog Posts" || r["description"] != "updated by terraform" {
+							return fmt.Errorf("expected only the name to change, got %v", r)
+						}
+						return nil
+					},
+				),
+			},
+			// a stack deleted in the UI is planned to be created again
+			{
+				PreConfig: func() {
+					api.Remove(contentstacktest.Stacks, apiKey)
+				},
+				Config: config("updated by terraform"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("contentstack_stack.test", plancheck.ResourceActionCreate),
+					},
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccStackResourceDeletedBeforeDestroy(t *testing.T) {
+	api := testAccFakeAPI(t)
+
+	var apiKey string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStackResourceConfig(api, `
+  name = "Blog"
+`),
+				Check: func(s *terraform.State) error {
+					apiKey = s.RootModule().Resources["contentstack_stack.test"].Primary.ID
+					return nil
+				},
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					// like testAccRemoveBeforeDestroy, for an api key which is
+					// only known once the Check of the step has run
+					PostApplyPostRefresh: []plancheck.PlanCheck{
+						testAccPlanCheckFunc(func() error {
+							if !api.Remove(contentstacktest.Stacks, apiKey) {
+								return fmt.Errorf("expected Stack %#v to exist", apiKey)
+							}
+							return nil
+						}),
+					},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,354 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &StackShareResource{}
+var _ resource.ResourceWithImportState = &StackShareResource{}
+var _ resource.ResourceWithModifyPlan = &StackShareResource{}
+
+func NewStackShareResource() resource.Resource {
+	return &StackShareResource{}
+}
+
+// StackShareResource defines the resource implementation.
+type StackShareResource struct {
+	clients *clientFactory
+}
+
+// StackShareResourceModel describes the resource data model.
+type StackShareResourceModel struct {
+	Email       types.String   `tfsdk:"email"`
+	ID          types.String   `tfsdk:"id"`
+	Roles       types.Set      `tfsdk:"roles"`
+	StackAPIKey types.String   `tfsdk:"stack_api_key"`
+	UserUID     types.String   `tfsdk:"user_uid"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Update records the collaborator as read from the stack; an invitation which
+// has not been accepted yet has no roles to report so the planned roles stay.
+func (data *StackShareResourceModel) Update(c *stackCollaboratorJSON) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.ID = data.Email
+	data.UserUID = types.StringValue(c.UID)
+	if len(c.Roles) > 0 {
+		data.Roles, diags = types.SetValueFrom(context.Background(), types.StringType, []string(c.Roles))
+	}
+
+	return diags
+}
+
+func (data *StackShareResourceModel) RoleUIDs() ([]string, diag.Diagnostics) {
+	var roles []string
+	diags := data.Roles.ElementsAs(context.Background(), &roles, false)
+	return roles, diags
+}
+
+func (r *StackShareResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stack_share"
+}
+
+func (r *StackShareResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Stack Share resource; invites a user to a stack with a set of roles",
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(ctx),
+		},
+
+		Attributes: map[string]schema.Attribute{
+			"email": schema.StringAttribute{
+				MarkdownDescription: "email address of the user to share the Stack with",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "internal terraform resource id (matches the email when the Stack Share has been created/imported)",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"roles": schema.SetAttribute{
+				MarkdownDescription: "uids of the roles to give the user on the Stack",
+				ElementType:         types.StringType,
+				Required:            true,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+			"stack_api_key": schema.StringAttribute{
+				MarkdownDescription: stackAPIKeyDescription,
+				Optional:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"user_uid": schema.StringAttribute{
+				MarkdownDescription: "uid of the user the Stack is shared with",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *StackShareResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	clients, ok := req.ProviderData.(*clientFactory)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *clientFactory, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.clients = clients
+}
+
+// findCollaborator returns the collaborator of a stack with the given email.
+func findCollaborator(s *stackJSON, email string) *stackCollaboratorJSON {
+	for i, c := range s.Collaborators {
+		if strings.EqualFold(c.Email, email) {
+			return &s.Collaborators[i]
+		}
+	}
+	return nil
+}
+
+func (r *StackShareResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *StackShareResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, dg := data.Timeouts.Create(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	roles, dg := data.RoleUIDs()
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := shareStack(ctx, client, data.Email.ValueString(), roles)
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to share Stack with %#v", data.Email.ValueString()), err, nil)
+		return
+	}
+
+	s, err := getStackJSON(ctx, client, true)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Unable to read Stack collaborators", err, nil)
+		return
+	}
+
+	data.ID = data.Email
+	data.UserUID = types.StringValue("")
+	if c := findCollaborator(s, data.Email.ValueString()); c != nil {
+		data.UserUID = types.StringValue(c.UID)
+	}
+
+	tflog.Trace(ctx, "shared a Stack", map[string]interface{}{
+		"email": data.Email.ValueString(),
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StackShareResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *StackShareResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, dg := data.Timeouts.Read(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	s, err := getStackJSON(ctx, client, true)
+	if err != nil {
+		addClientError(&resp.Diagnostics, "Unable to read Stack collaborators", err, nil)
+		return
+	}
+
+	c := findCollaborator(s, data.Email.ValueString())
+	if c == nil {
+		// the user has left or been removed from the stack
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(data.Update(c)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StackShareResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *StackShareResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, dg := data.Timeouts.Update(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	roles, dg := data.RoleUIDs()
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the user uid is only known once the invitation has been accepted
+	if data.UserUID.ValueString() == "" {
+		s, err := getStackJSON(ctx, client, true)
+		if err != nil {
+			addClientError(&resp.Diagnostics, "Unable to read Stack collaborators", err, nil)
+			return
+		}
+		if c := findCollaborator(s, data.Email.ValueString()); c != nil {
+			data.UserUID = types.StringValue(c.UID)
+		}
+	}
+
+	if data.UserUID.ValueString() == "" {
+		resp.Diagnostics.AddError(
+			"Client Error",
+			fmt.Sprintf("Unable to update the roles of %#v, who has not accepted the invitation to the Stack yet", data.Email.ValueString()),
+		)
+		return
+	}
+
+	err := updateStackUserRoles(ctx, client, data.UserUID.ValueString(), roles)
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to update the roles of %#v", data.Email.ValueString()), err, nil)
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *StackShareResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *StackShareResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, dg := data.Timeouts.Delete(ctx, defaultOperationTimeout)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, dg := r.clients.ForStack(data.StackAPIKey)
+	resp.Diagnostics.Append(dg...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := unshareStack(ctx, client, data.Email.ValueString())
+	if err != nil {
+		addClientError(&resp.Diagnostics, fmt.Sprintf("Unable to unshare Stack with %#v", data.Email.ValueString()), err, nil)
+		return
+	}
+}
+
+func (r *StackShareResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to check when destroying, nor before the provider is configured
+	if req.Plan.Raw.IsNull() || r.clients == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(r.clients.ValidatePlan(ctx, req.Plan)...)
+}
+
+func (r *StackShareResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importStatePassthroughStackScopedID(ctx, path.Root("email"), req, resp)
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindCollaboratorRoles(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		body          string
+		email         string
+		expectedUID   string
+		expectedRoles roleUIDs
+	}
+	tests := map[string]testCase{
+		"roles as uids": {
+			body:          `{"collaborators":[{"uid":"u1","email":"dev@example.com","roles":["r1","r2"]}]}`,
+			email:         "dev@example.com",
+			expectedUID:   "u1",
+			expectedRoles: roleUIDs{"r1", "r2"},
+		},
+		"roles as objects and email in another case": {
+			body:          `{"collaborators":[{"uid":"u2","email":"Dev@Example.com","roles":[{"uid":"r3","name":"Developer"}]}]}`,
+			email:         "dev@example.com",
+			expectedUID:   "u2",
+			expectedRoles: roleUIDs{"r3"},
+		},
+		"not a collaborator": {
+			body:  `{"collaborators":[{"uid":"u1","email":"dev@example.com"}]}`,
+			email: "other@example.com",
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var s stackJSON
+			if err := json.Unmarshal([]byte(test.body), &s); err != nil {
+				t.Fatalf("got unexpected error: %s", err)
+			}
+
+			c := findCollaborator(&s, test.email)
+			if c == nil {
+				if test.expectedUID != "" {
+					t.Fatalf("expected collaborator %q, got none", test.expectedUID)
+				}
+				return
+			}
+
+			if c.UID != test.expectedUID {
+				t.Errorf("expected collaborator %q, got %q", test.expectedUID, c.UID)
+			}
+			if diff := cmp.Diff(c.Roles, test.expectedRoles); diff != "" {
+				t.Errorf("unexpected roles diff (+wanted, -got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+	"sync"
+)
+
+// clientFactory hands out one apiClient per stack API key so that a
+// single provider configuration can manage many stacks.
+//
+// It is what the provider passes to resources and data sources in place of a
+// single *apiClient.
+type clientFactory struct {
+	defaultAPIKey string
+	newClient     func(apiKey string) (*apiClient, error)
+
+	// stackScoped is set when the credentials are a management token, which
+	// only works for the stack of defaultAPIKey.
+	stackScoped bool
+
+	// strictVersioning refuses updates of objects which have changed since
+	// Terraform last read them; see checkVersion.
+	strictVersioning bool
+
+	mu      sync.Mutex
+	clients map[string]*apiClient
+}
+
+func newClientFactory(defaultAPIKey string, newClient func(apiKey string) (*apiClient, error)) *clientFactory {
+	return &clientFactory{
+		defaultAPIKey: defaultAPIKey,
+		newClient:     newClient,
+		clients:       make(map[string]*apiClient),
+	}
+}
+
+// Client returns the client for a stack API key, creating it on first use.
+// Use Unscoped for requests which are not made to a stack.
+func (f *clientFactory) Client(apiKey string) (*apiClient, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if c, ok := f.clients[apiKey]; ok {
+		return c, nil
+	}
+
+	c, err := f.newClient(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	f.clients[apiKey] = c
+
+	return c, nil
+}
+
+// Unscoped returns a client which sends no stack API key, for the
+// organization level requests such as creating a stack.
+func (f *clientFactory) Unscoped() (*apiClient, error) {
+	return f.Client("")
+}
+
+// ForStack returns the client for a resource or data source's stack_api_key,
+// falling back to the provider api_key when it is not set.
+func (f *clientFactory) ForStack(stackAPIKey types.String) (*apiClient, diag.Diagnostics) {
+	apiKey, diags := f.apiKeyFor(stackAPIKey)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	c, err := f.Client(apiKey)
+	if err != nil {
+		diags.AddError(
+			"Unable to Create ContentStack API Client",
+			"An unexpected error occurred when creating the ContentStack API client for a stack. "+
+				"If the error is not clear, please contact the provider developers.\n\n"+
+				"ContentStack Client Error: "+err.Error(),
+		)
+		return nil, diags
+	}
+
+	return c, diags
+}
+
+// apiKeyFor returns the API key of the stack which a stack_api_key names,
+// falling back to the provider api_key when it is not set.
+func (f *clientFactory) apiKeyFor(stackAPIKey types.String) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	apiKey := f.defaultAPIKey
+	if !stackAPIKey.IsNull() && !stackAPIKey.IsUnknown() && stackAPIKey.ValueString() != "" {
+		apiKey = stackAPIKey.ValueString()
+	}
+
+	if apiKey == "" {
+		diags.AddAttributeError(
+			path.Root("stack_api_key"),
+			"Missing ContentStack Stack API Key",
+			"There is no stack to manage as neither the stack_api_key of this resource nor the api_key of the provider is set. "+
+				"Set one of them, or use the CONTENTSTACK_API_KEY environment variable.",
+		)
+		return "", diags
+	}
+
+	if f.stackScoped && apiKey != f.defaultAPIKey {
+		diags.AddAttributeError(
+			path.Root("stack_api_key"),
+			"Unsupported ContentStack Stack API Key",
+			"The stack_api_key of this resource names another stack than the api_key of the provider, but the provider authenticates with a management token, "+
+				"which only works for the stack it was created in. "+
+				"Configure the provider with user credentials (authtoken, or email and password) or an OAuth app to manage other stacks, "+
+				"or use a provider configuration with the api_key and management_token of that stack.",
+		)
+		return "", diags
+	}
+
+	return apiKey, diags
+}
+
+// ValidatePlan checks the stack_api_key of a planned resource, so that a
+// stack which the credentials cannot reach fails the plan rather than the
+// apply.
+func (f *clientFactory) ValidatePlan(ctx context.Context, plan tfsdk.Plan) diag.Diagnostics {
+	var stackAPIKey types.String
+	diags := plan.GetAttribute(ctx, path.Root("stack_api_key"), &stackAPIKey)
+	if diags.HasError() || stackAPIKey.IsUnknown() {
+		return diags
+	}
+
+	_, dg := f.apiKeyFor(stackAPIKey)
+	diags.Append(dg...)
+	return diags
+}
+
+// stackAPIKeyDescription documents the stack_api_key attribute of every
+// resource and data source.
+const stackAPIKeyDescription = "API Key of the stack to manage; overrides the `api_key` of the provider so that one provider configuration can manage many stacks. Managing another stack needs user or OAuth credentials, as a management token only works for the stack it was created in."
+
+// importStatePassthroughStackScopedID imports an ID of the form
+// [stack_api_key:]id, setting stack_api_key when it is given.
+func importStatePassthroughStackScopedID(ctx context.Context, attrPath path.Path, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id := req.ID
+	if stackAPIKey, rest, ok := strings.Cut(req.ID, ":"); ok {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("stack_api_key"), stackAPIKey)...)
+		id = rest
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, attrPath, id)...)
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/davidalpert/go-contentstack/v1/management"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestClientFactoryForStack(t *testing.T) {
+	t.Parallel()
+
+	created := map[string]int{}
+	clients := newClientFactory("default-key", func(apiKey string) (*apiClient, error) {
+		created[apiKey]++
+		return newAPIClient(&management.Configuration{Host: "http://localhost", Key: apiKey}), nil
+	})
+
+	defaultClient, diags := clients.ForStack(types.StringNull())
+	if diags.HasError() {
+		t.Fatalf("got unexpected error: %s", diags)
+	}
+	otherClient, diags := clients.ForStack(types.StringValue("other-key"))
+	if diags.HasError() {
+		t.Fatalf("got unexpected error: %s", diags)
+	}
+	if defaultClient == otherClient {
+		t.Error("expected a separate client per stack")
+	}
+
+	if got := otherClient.resty.Header.Get("api_key"); got != "other-key" {
+		t.Errorf("expected api_key header %q, got %q", "other-key", got)
+	}
+
+	again, _ := clients.ForStack(types.StringValue("default-key"))
+	if again != defaultClient {
+		t.Error("expected the client for a stack to be reused")
+	}
+	if created["default-key"] != 1 || created["other-key"] != 1 {
+		t.Errorf("expected one client per stack, got %v", created)
+	}
+}
+
+func TestClientFactoryForStackWithoutAPIKey(t *testing.T) {
+	t.Parallel()
+
+	clients := newClientFactory("", func(apiKey string) (*apiClient, error) {
+		t.Fatal("expected no client to be created")
+		return nil, nil
+	})
+
+	if _, diags := clients.ForStack(types.StringNull()); !diags.HasError() {
+		t.Fatal("expected error, got no error")
+	}
+}
+
+func TestClientFactoryUnscoped(t *testing.T) {
+	t.Parallel()
+
+	clients := newClientFactory("default-key", func(apiKey string) (*apiClient, error) {
+		return newAPIClient(&management.Configuration{Host: "http://localhost", Key: apiKey}), nil
+	})
+
+	unscoped, err := clients.Unscoped()
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	if _, ok := unscoped.resty.Header["Api_key"]; ok {
+		t.Errorf("expected no api_key header, got %q", unscoped.resty.Header.Get("api_key"))
+	}
+
+	defaultClient, _ := clients.ForStack(types.StringNull())
+	if unscoped == defaultClient {
+		t.Error("expected the unscoped client not to be the client of the default stack")
+	}
+}
+
+func TestClientFactoryForStackWithManagementToken(t *testing.T) {
+	t.Parallel()
+
+	clients := newClientFactory("default-key", func(apiKey string) (*apiClient, error) {
+		return newAPIClient(&management.Configuration{Host: "http://localhost", Key: apiKey}), nil
+	})
+	clients.stackScoped = true
+
+	if _, diags := clients.ForStack(types.StringValue("default-key")); diags.HasError() {
+		t.Errorf("got unexpected error: %s", diags)
+	}
+	if _, diags := clients.ForStack(types.StringValue("other-key")); !diags.HasError() {
+		t.Error("expected error, got no error")
+	}
+}
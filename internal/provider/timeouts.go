@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"time"
+)
+
+// defaultRequestTimeout bounds a single request when the provider has no
+// request_timeout.
+const defaultRequestTimeout = time.Minute
+
+// defaultOperationTimeout bounds a create, read, update or delete when the
+// resource has no timeouts block; each request is also bounded by the
+// provider request_timeout.
+const defaultOperationTimeout = 20 * time.Minute
+
+// timeoutsBlock is the timeouts block shared by every resource.
+func timeoutsBlock(ctx context.Context) schema.Block {
+	return timeouts.Block(ctx, timeouts.Opts{
+		Create: true,
+		Read:   true,
+		Update: true,
+		Delete: true,
+	})
+}
+
+// nullTimeouts is an unset timeouts block, for states which predate it.
+func nullTimeouts() timeouts.Value {
+	return timeouts.Value{
+		Object: types.ObjectNull(map[string]attr.Type{
+			"create": types.StringType,
+			"read":   types.StringType,
+			"update": types.StringType,
+			"delete": types.StringType,
+		}),
+	}
+}
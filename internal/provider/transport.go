@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// transportConfig describes how the provider reaches the API through proxies
+// and TLS-inspecting middleboxes.
+type transportConfig struct {
+	ProxyURL           string
+	CACertFile         string
+	CACertPEM          string
+	ClientCert         string
+	ClientKey          string
+	InsecureSkipVerify bool
+}
+
+// newTransport builds the HTTP transport shared by every client of the
+// provider; without any settings it behaves like http.DefaultTransport.
+func newTransport(cfg transportConfig) (*http.Transport, error) {
+	defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected default HTTP transport %T", http.DefaultTransport)
+	}
+	transport := defaultTransport.Clone()
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %#v", cfg.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	caCertPEM := []byte(cfg.CACertPEM)
+	if cfg.CACertFile != "" {
+		b, err := os.ReadFile(cfg.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA certificate file: %w", err)
+		}
+		caCertPEM = b
+	}
+
+	if len(caCertPEM) > 0 {
+		// trust the extra CA on top of the system roots
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caCertPEM) {
+			return nil, fmt.Errorf("no PEM encoded certificates found in the CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(cfg.ClientCert), []byte(cfg.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
+
+// useTransport sends the client's requests through the given transport.
+func useTransport(c *apiClient, transport http.RoundTripper) {
+	c.resty.SetTransport(transport)
+}
@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davidalpert/go-contentstack/v1/management"
+)
+
+func localesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"locales":[]}`))
+	})
+}
+
+func getLocalesThrough(t *testing.T, host string, cfg transportConfig) error {
+	t.Helper()
+
+	transport, err := newTransport(cfg)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	client := newAPIClient(&management.Configuration{Host: host, Key: "api-key", Token: "management-token"})
+	useTransport(client, transport)
+
+	_, err = getLocales(context.Background(), client)
+	return err
+}
+
+func certificatePEM(der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestTransportCACertificate(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(localesHandler())
+	defer server.Close()
+
+	if err := getLocalesThrough(t, server.URL, transportConfig{}); err == nil {
+		t.Error("expected an untrusted certificate error, got no error")
+	}
+
+	if err := getLocalesThrough(t, server.URL, transportConfig{CACertPEM: certificatePEM(server.Certificate().Raw)}); err != nil {
+		t.Errorf("got unexpected error: %s", err)
+	}
+
+	if err := getLocalesThrough(t, server.URL, transportConfig{InsecureSkipVerify: true}); err != nil {
+		t.Errorf("got unexpected error: %s", err)
+	}
+}
+
+func TestTransportClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	server := httptest.NewUnstartedServer(localesHandler())
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	caCertPEM := certificatePEM(server.Certificate().Raw)
+
+	if err := getLocalesThrough(t, server.URL, transportConfig{CACertPEM: caCertPEM}); err == nil {
+		t.Error("expected a missing client certificate error, got no error")
+	}
+
+	cfg := transportConfig{
+		CACertPEM:  caCertPEM,
+		ClientCert: certificatePEM(der),
+		ClientKey:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+	if err := getLocalesThrough(t, server.URL, cfg); err != nil {
+		t.Errorf("got unexpected error: %s", err)
+	}
+}
+
+func TestTransportProxy(t *testing.T) {
+	t.Parallel()
+
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		localesHandler().ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	if err := getLocalesThrough(t, "http://api.contentstack.invalid", transportConfig{ProxyURL: proxy.URL}); err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+	if proxied != "http://api.contentstack.invalid/v3/locales" {
+		t.Errorf("expected the request to go through the proxy, got %q", proxied)
+	}
+}
+
+func TestNewTransportInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]transportConfig{
+		"proxy url without scheme": {ProxyURL: "proxy.example.com:3128"},
+		"missing ca file":          {CACertFile: "testdata/missing.pem"},
+		"ca pem without certs":     {CACertPEM: "not a certificate"},
+		"client cert without key":  {ClientCert: "not a certificate"},
+	}
+
+	for name, cfg := range tests {
+		name, cfg := name, cfg
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := newTransport(cfg); err == nil {
+				t.Fatal("expected error, got no error")
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// checkVersion guards an update under strict_versioning: it fails when the
+// version on the stack is not the version Terraform last read into the state,
+// which means someone changed the object since and the update would overwrite
+// their change. A state without a version, written before the version was
+// tracked, is not checked.
+//
+// The check is best-effort. The Contentstack API ignores `_version` on the
+// update of an environment, global field or content type and has no
+// conditional update, so the check and the update are separate requests and
+// a change made between them still goes unnoticed.
+func checkVersion(kind, name string, stateVersion types.Int64, serverVersion int) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if stateVersion.IsNull() || stateVersion.IsUnknown() || stateVersion.ValueInt64() == int64(serverVersion) {
+		return diags
+	}
+
+	diags.AddAttributeError(
+		path.Root("version"),
+		"Conflicting Change",
+		fmt.Sprintf("%s %#v has been changed outside Terraform since it was last read: the state has version %d and the stack has version %d. "+
+			"Refresh and review the change, for instance by running terraform plan again, before overwriting it; strict_versioning is enabled.",
+			kind, name, stateVersion.ValueInt64(), serverVersion),
+	)
+
+	return diags
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCheckVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		stateVersion  types.Int64
+		serverVersion int
+		wantError     bool
+	}{
+		{"unchanged", types.Int64Value(3), 3, false},
+		{"changed since read", types.Int64Value(3), 4, true},
+		{"no version in state", types.Int64Null(), 4, false},
+		{"unknown version", types.Int64Unknown(), 4, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			diags := checkVersion("Environment", "staging", tt.stateVersion, tt.serverVersion)
+			if diags.HasError() != tt.wantError {
+				t.Errorf("expected error %t, got %v", tt.wantError, diags)
+			}
+		})
+	}
+}
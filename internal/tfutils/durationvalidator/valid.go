@@ -0,0 +1,42 @@
+package durationvalidator
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"time"
+)
+
+var _ validator.String = validDuration{}
+
+type validDuration struct{}
+
+// Valid returns a string validator which checks that a known value is a
+// non-negative duration such as "30s" or "2m30s", as parsed by
+// time.ParseDuration.
+func Valid() validator.String {
+	return validDuration{}
+}
+
+func (v validDuration) Description(context.Context) string {
+	return `value must be a non-negative duration such as "30s" or "2m30s"`
+}
+
+func (v validDuration) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v validDuration) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	d, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil || d < 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			fmt.Sprintf("Attribute %q %s, got: %s", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
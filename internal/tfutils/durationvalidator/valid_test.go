@@ -0,0 +1,60 @@
+package durationvalidator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValid(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		configValue types.String
+		expectError bool
+	}
+	tests := map[string]testCase{
+		"seconds": {
+			configValue: types.StringValue("30s"),
+		},
+		"compound": {
+			configValue: types.StringValue("2m30s"),
+		},
+		"unknown": {
+			configValue: types.StringUnknown(),
+		},
+		"missing unit": {
+			configValue: types.StringValue("30"),
+			expectError: true,
+		},
+		"negative": {
+			configValue: types.StringValue("-1s"),
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			request := validator.StringRequest{
+				Path:        path.Root("test"),
+				ConfigValue: test.configValue,
+			}
+			response := validator.StringResponse{}
+			Valid().ValidateString(context.Background(), request, &response)
+
+			if !response.Diagnostics.HasError() && test.expectError {
+				t.Fatal("expected error, got no error")
+			}
+
+			if response.Diagnostics.HasError() && !test.expectError {
+				t.Fatalf("got unexpected error: %s", response.Diagnostics)
+			}
+		})
+	}
+}
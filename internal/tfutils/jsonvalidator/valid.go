@@ -0,0 +1,40 @@
+package jsonvalidator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = validJSON{}
+
+type validJSON struct{}
+
+// Valid returns a string validator which checks that a known value is a
+// well-formed JSON document.
+func Valid() validator.String {
+	return validJSON{}
+}
+
+func (v validJSON) Description(context.Context) string {
+	return "value must be a valid JSON document"
+}
+
+func (v validJSON) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v validJSON) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if !json.Valid([]byte(req.ConfigValue.ValueString())) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid JSON",
+			fmt.Sprintf("Attribute %q %s, got: %s", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
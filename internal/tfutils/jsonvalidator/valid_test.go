@@ -0,0 +1,56 @@
+package jsonvalidator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValid(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		configValue types.String
+		expectError bool
+	}
+	tests := map[string]testCase{
+		"object": {
+			configValue: types.StringValue(`{"a": [1, 2]}`),
+		},
+		"scalar": {
+			configValue: types.StringValue(`"text"`),
+		},
+		"unknown": {
+			configValue: types.StringUnknown(),
+		},
+		"malformed": {
+			configValue: types.StringValue(`{"a": `),
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			request := validator.StringRequest{
+				Path:        path.Root("test"),
+				ConfigValue: test.configValue,
+			}
+			response := validator.StringResponse{}
+			Valid().ValidateString(context.Background(), request, &response)
+
+			if !response.Diagnostics.HasError() && test.expectError {
+				t.Fatal("expected error, got no error")
+			}
+
+			if response.Diagnostics.HasError() && !test.expectError {
+				t.Fatalf("got unexpected error: %s", response.Diagnostics)
+			}
+		})
+	}
+}
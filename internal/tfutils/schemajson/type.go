@@ -0,0 +1,75 @@
+package schemajson
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var _ basetypes.StringTypable = Type{}
+var _ xattr.TypeWithValidate = Type{}
+
+// Type is a string type holding a Contentstack schema as a JSON array of
+// field definitions.
+type Type struct {
+	basetypes.StringType
+}
+
+func (t Type) String() string {
+	return "schemajson.Type"
+}
+
+func (t Type) Equal(o attr.Type) bool {
+	_, ok := o.(Type)
+	return ok
+}
+
+func (t Type) ValueType(ctx context.Context) attr.Value {
+	return Value{}
+}
+
+func (t Type) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return Value{StringValue: in}, nil
+}
+
+func (t Type) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return Value{StringValue: stringValue}, nil
+}
+
+// Validate ensures a known value is a JSON array of objects.
+func (t Type) Validate(ctx context.Context, in tftypes.Value, p path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !in.IsKnown() || in.IsNull() {
+		return diags
+	}
+
+	var s string
+	if err := in.As(&s); err != nil {
+		diags.AddAttributeError(p, "Invalid Schema JSON", fmt.Sprintf("unable to read value: %s", err))
+		return diags
+	}
+
+	var fields []map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &fields); err != nil {
+		diags.AddAttributeError(p, "Invalid Schema JSON", fmt.Sprintf("expected a JSON array of field definitions: %s", err))
+	}
+
+	return diags
+}
@@ -0,0 +1,122 @@
+package schemajson
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"reflect"
+)
+
+var _ basetypes.StringValuableWithSemanticEquals = Value{}
+
+// serverDefaults are the properties Contentstack adds to every field when they
+// are not supplied; a property holding its default is treated as if it were
+// absent so that the server echoing it back does not produce a diff.
+var serverDefaults = map[string]interface{}{
+	"mandatory":       false,
+	"multiple":        false,
+	"unique":          false,
+	"non_localizable": false,
+	"indexed":         false,
+	"inbuilt_model":   false,
+	"description":     "",
+}
+
+// Value holds a Contentstack schema JSON document.
+type Value struct {
+	basetypes.StringValue
+}
+
+// NewValue returns a known Value holding s.
+func NewValue(s string) Value {
+	return Value{StringValue: basetypes.NewStringValue(s)}
+}
+
+// NullValue returns a null Value.
+func NullValue() Value {
+	return Value{StringValue: basetypes.NewStringNull()}
+}
+
+func (v Value) Type(ctx context.Context) attr.Type {
+	return Type{}
+}
+
+func (v Value) Equal(o attr.Value) bool {
+	other, ok := o.(Value)
+	if !ok {
+		return false
+	}
+
+	return v.StringValue.Equal(other.StringValue)
+}
+
+// StringSemanticEquals reports whether both documents describe the same schema,
+// ignoring whitespace, key order and properties holding their server defaults.
+func (v Value) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(Value)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got value type %T. Please report this issue to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	prior, err := Normalize(v.ValueString())
+	if err != nil {
+		return false, diags
+	}
+
+	updated, err := Normalize(newValue.ValueString())
+	if err != nil {
+		return false, diags
+	}
+
+	return reflect.DeepEqual(prior, updated), diags
+}
+
+// Normalize decodes a JSON document and strips null values, empty objects and
+// properties holding their server defaults, so that two normalized documents
+// can be compared with reflect.DeepEqual.
+func Normalize(s string) (interface{}, error) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(s), &doc); err != nil {
+		return nil, err
+	}
+
+	return normalize(doc), nil
+}
+
+func normalize(doc interface{}) interface{} {
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(d))
+		for k, v := range d {
+			v = normalize(v)
+			if v == nil {
+				continue
+			}
+			if def, ok := serverDefaults[k]; ok && reflect.DeepEqual(def, v) {
+				continue
+			}
+			if m, ok := v.(map[string]interface{}); ok && len(m) == 0 {
+				continue
+			}
+			result[k] = v
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(d))
+		for i, v := range d {
+			result[i] = normalize(v)
+		}
+		return result
+	default:
+		return d
+	}
+}
@@ -0,0 +1,65 @@
+package schemajson
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStringSemanticEquals(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		priorValue    Value
+		newValue      Value
+		expectedEqual bool
+	}
+	tests := map[string]testCase{
+		"identical": {
+			priorValue:    NewValue(`[{"uid":"title","data_type":"text"}]`),
+			newValue:      NewValue(`[{"uid":"title","data_type":"text"}]`),
+			expectedEqual: true,
+		},
+		"key order and whitespace": {
+			priorValue:    NewValue(`[{"uid":"title","data_type":"text"}]`),
+			newValue:      NewValue(`[ { "data_type": "text", "uid": "title" } ]`),
+			expectedEqual: true,
+		},
+		"server defaults added": {
+			priorValue:    NewValue(`[{"uid":"title","data_type":"text"}]`),
+			newValue:      NewValue(`[{"uid":"title","data_type":"text","mandatory":false,"unique":false,"field_metadata":{"description":""}}]`),
+			expectedEqual: true,
+		},
+		"non-default value added": {
+			priorValue:    NewValue(`[{"uid":"title","data_type":"text"}]`),
+			newValue:      NewValue(`[{"uid":"title","data_type":"text","mandatory":true}]`),
+			expectedEqual: false,
+		},
+		"field order changed": {
+			priorValue:    NewValue(`[{"uid":"a","data_type":"text"},{"uid":"b","data_type":"text"}]`),
+			newValue:      NewValue(`[{"uid":"b","data_type":"text"},{"uid":"a","data_type":"text"}]`),
+			expectedEqual: false,
+		},
+		"invalid json": {
+			priorValue:    NewValue(`[{"uid":"title","data_type":"text"}]`),
+			newValue:      NewValue(`[{`),
+			expectedEqual: false,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			equal, diags := test.priorValue.StringSemanticEquals(context.Background(), test.newValue)
+
+			if diags.HasError() {
+				t.Fatalf("got unexpected error: %s", diags)
+			}
+
+			if equal != test.expectedEqual {
+				t.Errorf("expected %t, got %t", test.expectedEqual, equal)
+			}
+		})
+	}
+}
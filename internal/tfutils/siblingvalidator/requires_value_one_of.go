@@ -0,0 +1,84 @@
+package siblingvalidator
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
+)
+
+var _ validator.Bool = RequiresValueOneOfValidator{}
+var _ validator.Float64 = RequiresValueOneOfValidator{}
+var _ validator.String = RequiresValueOneOfValidator{}
+
+// RequiresValueOneOfValidator validates Bool, Float64 and String attributes
+// against the value of a sibling attribute.
+type RequiresValueOneOfValidator struct {
+	attrName string
+	values   []string
+}
+
+// RequiresValueOneOf returns a validator which only allows the attribute to be
+// configured when the sibling attribute attrName holds one of the given values.
+//
+// Unknown sibling values are skipped so that validation can be retried once
+// they are known.
+func RequiresValueOneOf(attrName string, values ...string) RequiresValueOneOfValidator {
+	return RequiresValueOneOfValidator{
+		attrName: attrName,
+		values:   values,
+	}
+}
+
+func (v RequiresValueOneOfValidator) Description(context.Context) string {
+	return fmt.Sprintf("may only be set when %s is one of: %s", v.attrName, strings.Join(v.values, ", "))
+}
+
+func (v RequiresValueOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v RequiresValueOneOfValidator) ValidateBool(ctx context.Context, req validator.BoolRequest, resp *validator.BoolResponse) {
+	resp.Diagnostics.Append(v.validate(ctx, req.Config, req.Path, req.ConfigValue)...)
+}
+
+func (v RequiresValueOneOfValidator) ValidateFloat64(ctx context.Context, req validator.Float64Request, resp *validator.Float64Response) {
+	resp.Diagnostics.Append(v.validate(ctx, req.Config, req.Path, req.ConfigValue)...)
+}
+
+func (v RequiresValueOneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	resp.Diagnostics.Append(v.validate(ctx, req.Config, req.Path, req.ConfigValue)...)
+}
+
+func (v RequiresValueOneOfValidator) validate(ctx context.Context, config tfsdk.Config, p path.Path, value attr.Value) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if value.IsNull() || value.IsUnknown() {
+		return diags
+	}
+
+	var sibling types.String
+	diags.Append(config.GetAttribute(ctx, p.ParentPath().AtName(v.attrName), &sibling)...)
+	if diags.HasError() || sibling.IsNull() || sibling.IsUnknown() {
+		return diags
+	}
+
+	for _, want := range v.values {
+		if sibling.ValueString() == want {
+			return diags
+		}
+	}
+
+	diags.AddAttributeError(
+		p,
+		"Invalid Attribute Combination",
+		fmt.Sprintf("Attribute %q %s, got %q", p, v.Description(ctx), sibling.ValueString()),
+	)
+
+	return diags
+}
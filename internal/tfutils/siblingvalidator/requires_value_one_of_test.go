@@ -0,0 +1,80 @@
+package siblingvalidator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestRequiresValueOneOf(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		siblingValue tftypes.Value
+		configValue  types.String
+		expectError  bool
+	}
+	tests := map[string]testCase{
+		"sibling matches": {
+			siblingValue: tftypes.NewValue(tftypes.String, "isodate"),
+			configValue:  types.StringValue("2023-01-01"),
+		},
+		"sibling does not match": {
+			siblingValue: tftypes.NewValue(tftypes.String, "text"),
+			configValue:  types.StringValue("2023-01-01"),
+			expectError:  true,
+		},
+		"sibling unknown": {
+			siblingValue: tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			configValue:  types.StringValue("2023-01-01"),
+		},
+		"value not configured": {
+			siblingValue: tftypes.NewValue(tftypes.String, "text"),
+			configValue:  types.StringNull(),
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			s := schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"data_type":    schema.StringAttribute{Required: true},
+					"default_date": schema.StringAttribute{Optional: true},
+				},
+			}
+			config := tfsdk.Config{
+				Schema: s,
+				Raw: tftypes.NewValue(s.Type().TerraformType(ctx), map[string]tftypes.Value{
+					"data_type":    test.siblingValue,
+					"default_date": tftypes.NewValue(tftypes.String, nil),
+				}),
+			}
+
+			request := validator.StringRequest{
+				Path:        path.Root("default_date"),
+				ConfigValue: test.configValue,
+				Config:      config,
+			}
+			response := validator.StringResponse{}
+			RequiresValueOneOf("data_type", "isodate").ValidateString(ctx, request, &response)
+
+			if !response.Diagnostics.HasError() && test.expectError {
+				t.Fatal("expected error, got no error")
+			}
+
+			if response.Diagnostics.HasError() && !test.expectError {
+				t.Fatalf("got unexpected error: %s", response.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package siblingvalidator
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"strings"
+)
+
+var _ validator.Bool = TrueRequiresOneOfValidator{}
+
+// TrueRequiresOneOfValidator validates that a Bool attribute which is true
+// comes with at least one of its sibling attributes.
+type TrueRequiresOneOfValidator struct {
+	attrNames []string
+}
+
+// TrueRequiresOneOf returns a validator which only allows the attribute to be
+// true when at least one of the sibling attributes attrNames is configured.
+//
+// Unknown values are skipped so that validation can be retried once they are
+// known.
+func TrueRequiresOneOf(attrNames ...string) TrueRequiresOneOfValidator {
+	return TrueRequiresOneOfValidator{
+		attrNames: attrNames,
+	}
+}
+
+func (v TrueRequiresOneOfValidator) Description(context.Context) string {
+	return fmt.Sprintf("may only be true when at least one of %s is set", strings.Join(v.attrNames, ", "))
+}
+
+func (v TrueRequiresOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v TrueRequiresOneOfValidator) ValidateBool(ctx context.Context, req validator.BoolRequest, resp *validator.BoolResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() || !req.ConfigValue.ValueBool() {
+		return
+	}
+
+	for _, name := range v.attrNames {
+		var sibling attr.Value
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, req.Path.ParentPath().AtName(name), &sibling)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !sibling.IsNull() {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid Attribute Combination",
+		fmt.Sprintf("Attribute %q %s", req.Path, v.Description(ctx)),
+	)
+}
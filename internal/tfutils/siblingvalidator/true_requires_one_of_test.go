@@ -0,0 +1,92 @@
+package siblingvalidator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestTrueRequiresOneOf(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		startDate   tftypes.Value
+		endDate     tftypes.Value
+		configValue types.Bool
+		expectError bool
+	}
+	tests := map[string]testCase{
+		"true with one sibling": {
+			startDate:   tftypes.NewValue(tftypes.String, nil),
+			endDate:     tftypes.NewValue(tftypes.String, "2023-12-31"),
+			configValue: types.BoolValue(true),
+		},
+		"true without siblings": {
+			startDate:   tftypes.NewValue(tftypes.String, nil),
+			endDate:     tftypes.NewValue(tftypes.String, nil),
+			configValue: types.BoolValue(true),
+			expectError: true,
+		},
+		"true with unknown sibling": {
+			startDate:   tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			endDate:     tftypes.NewValue(tftypes.String, nil),
+			configValue: types.BoolValue(true),
+		},
+		"false without siblings": {
+			startDate:   tftypes.NewValue(tftypes.String, nil),
+			endDate:     tftypes.NewValue(tftypes.String, nil),
+			configValue: types.BoolValue(false),
+		},
+		"value not configured": {
+			startDate:   tftypes.NewValue(tftypes.String, nil),
+			endDate:     tftypes.NewValue(tftypes.String, nil),
+			configValue: types.BoolNull(),
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			s := schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"date_range": schema.BoolAttribute{Optional: true},
+					"end_date":   schema.StringAttribute{Optional: true},
+					"start_date": schema.StringAttribute{Optional: true},
+				},
+			}
+			config := tfsdk.Config{
+				Schema: s,
+				Raw: tftypes.NewValue(s.Type().TerraformType(ctx), map[string]tftypes.Value{
+					"date_range": tftypes.NewValue(tftypes.Bool, nil),
+					"end_date":   test.endDate,
+					"start_date": test.startDate,
+				}),
+			}
+
+			request := validator.BoolRequest{
+				Path:        path.Root("date_range"),
+				ConfigValue: test.configValue,
+				Config:      config,
+			}
+			response := validator.BoolResponse{}
+			TrueRequiresOneOf("start_date", "end_date").ValidateBool(ctx, request, &response)
+
+			if !response.Diagnostics.HasError() && test.expectError {
+				t.Fatal("expected error, got no error")
+			}
+
+			if response.Diagnostics.HasError() && !test.expectError {
+				t.Fatalf("got unexpected error: %s", response.Diagnostics)
+			}
+		})
+	}
+}
@@ -44,6 +44,9 @@ func main() {
 
 	err := providerserver.Serve(context.Background(), provider.New(version, commit, date), opts)
 
+	// Serve returns once Terraform has stopped the provider
+	provider.CloseSessions(context.Background())
+
 	if err != nil {
 		log.Fatal(err.Error())
 	}